@@ -0,0 +1,156 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	simplebankpb "github.com/orlandorode97/simple-bank/generated/simplebank"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/access"
+	"github.com/orlandorode97/simple-bank/pkg/validations"
+	"github.com/orlandorode97/simple-bank/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CloseAccount moves the remaining balance to another account of the user and closes the account.
+// The account and its history are kept.
+func (s *GRPCServer) CloseAccount(ctx context.Context, req *simplebankpb.CloseAccountRequest) (*simplebankpb.CloseAccountResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "CloseAccountRequest is empty")
+	}
+
+	if err := isCloseAccountReqValid(req); err != nil {
+		return nil, err
+	}
+
+	payload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authorizedAccount(ctx, req.GetAccountId(), access.ActionManage, 0); err != nil {
+		return nil, err
+	}
+
+	reason := req.GetReason()
+	if reason == "" {
+		reason = "closed by the owner"
+	}
+
+	result, err := s.store.CloseAccountTx(ctx, store.CloseAccountTxParams{
+		AccountID:      req.GetAccountId(),
+		SweepAccountID: req.GetSweepAccountId(),
+		ClosedBy:       payload.Username,
+		Reason:         reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, status.Errorf(codes.NotFound, "%v", err)
+		case errors.Is(err, store.ErrInvalidSweepAccount):
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		case errors.Is(err, store.ErrInvalidStatusTransition),
+			errors.Is(err, store.ErrAccountNotEmpty),
+			errors.Is(err, store.ErrAccountNotActive),
+			errors.Is(err, store.ErrExchangeRateNotFound):
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "unable to close account: %v", err)
+	}
+
+	resp := &simplebankpb.CloseAccountResponse{
+		Account: convertAccount(result.Account),
+	}
+
+	if result.Sweep != nil {
+		resp.Sweep = &simplebankpb.CreateTransferResponse{
+			Transfer:    convertTransfer(result.Sweep.Transfer),
+			FromAccount: convertAccount(result.Sweep.FromAccount),
+			ToAccount:   convertAccount(result.Sweep.ToAccount),
+			FromEntry:   convertEntry(result.Sweep.FromEntry),
+			ToEntry:     convertEntry(result.Sweep.ToEntry),
+		}
+	}
+
+	return resp, nil
+}
+
+// MovePocketFunds instantly moves funds to another pocket of the user in the same currency.
+func (s *GRPCServer) MovePocketFunds(ctx context.Context, req *simplebankpb.MovePocketFundsRequest) (*simplebankpb.MovePocketFundsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "MovePocketFundsRequest is empty")
+	}
+
+	if err := isMovePocketFundsReqValid(req); err != nil {
+		return nil, err
+	}
+
+	// The to account is verified by the move itself, it must belong to the same user.
+	if _, err := s.authorizedAccount(ctx, req.GetFromAccountId(), access.ActionSpend, req.GetAmount()); err != nil {
+		return nil, err
+	}
+
+	result, err := s.store.MovePocketFundsTx(ctx, store.MovePocketFundsTxParams{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, status.Errorf(codes.NotFound, "%v", err)
+		case errors.Is(err, store.ErrInvalidPocketMove):
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		case errors.Is(err, store.ErrInsufficientFunds), errors.Is(err, store.ErrAccountNotActive):
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "unable to move pocket funds: %v", err)
+	}
+
+	return &simplebankpb.MovePocketFundsResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+	}, nil
+}
+
+// authorizedAccount valids the account exists and the authenticated user is a member allowed to do the action,
+// amount is checked against the spend limit of the member when the action spends funds.
+func (s *GRPCServer) authorizedAccount(ctx context.Context, accountID int64, action access.Action, amount int64) (simplebanksql.Account, error) {
+	payload, err := payloadFromContext(ctx)
+	if err != nil {
+		return simplebanksql.Account{}, err
+	}
+
+	account, err := s.store.AuthorizeAccount(ctx, store.AuthorizeAccountParams{
+		AccountID: accountID,
+		Username:  payload.Username,
+		Action:    action,
+		Amount:    amount,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows), errors.Is(err, store.ErrNotAccountMember):
+			return account, status.Errorf(codes.NotFound, "account [%d] not found", accountID)
+		case errors.Is(err, access.ErrForbidden):
+			return account, status.Errorf(codes.PermissionDenied, "%v", err)
+		}
+		return account, status.Errorf(codes.Internal, "unable to authorize account: %v", err)
+	}
+
+	return account, nil
+}
+
+func isCloseAccountReqValid(req *simplebankpb.CloseAccountRequest) error {
+	closeAccountValidator := validations.NewCloseAccountValidator(req)
+	return validations.BuildErrDetails(closeAccountValidator, "CloseAccountRequest error")
+}
+
+func isMovePocketFundsReqValid(req *simplebankpb.MovePocketFundsRequest) error {
+	movePocketFundsValidator := validations.NewMovePocketFundsValidator(req)
+	return validations.BuildErrDetails(movePocketFundsValidator, "MovePocketFundsRequest error")
+}
@@ -0,0 +1,284 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	simplebankpb "github.com/orlandorode97/simple-bank/generated/simplebank"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/validations"
+	"github.com/orlandorode97/simple-bank/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SearchUsers looks up users whose username, full name or email contains the query, ordered by username.
+func (s *GRPCServer) SearchUsers(ctx context.Context, req *simplebankpb.SearchUsersRequest) (*simplebankpb.SearchUsersResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "SearchUsersRequest is empty")
+	}
+
+	if err := isSearchUsersReqValid(req); err != nil {
+		return nil, err
+	}
+
+	users, err := s.store.SearchUsers(ctx, simplebanksql.SearchUsersParams{
+		Query:      req.GetQuery(),
+		PageLimit:  req.GetPageSize(),
+		PageOffset: (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to search users: %v", err)
+	}
+
+	resp := &simplebankpb.SearchUsersResponse{
+		Users: make([]*simplebankpb.User, 0, len(users)),
+	}
+
+	for _, user := range users {
+		resp.Users = append(resp.Users, convertUser(user))
+	}
+
+	return resp, nil
+}
+
+// UpdateUserRole changes the role of a user, it applies to the tokens issued from the next login or refresh.
+// Admins can't change their own role so there is always an admin left.
+func (s *GRPCServer) UpdateUserRole(ctx context.Context, req *simplebankpb.UpdateUserRoleRequest) (*simplebankpb.UpdateUserRoleResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "UpdateUserRoleRequest is empty")
+	}
+
+	if err := isUpdateUserRoleReqValid(req); err != nil {
+		return nil, err
+	}
+
+	payload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetUsername() == payload.Username {
+		return nil, status.Error(codes.FailedPrecondition, "unable to change your own role")
+	}
+
+	user, err := s.store.UpdateUserRole(ctx, simplebanksql.UpdateUserRoleParams{
+		Username: req.GetUsername(),
+		Role:     simplebanksql.UserRole(req.GetRole()),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user [%s] not found", req.GetUsername())
+		}
+		return nil, status.Errorf(codes.Internal, "unable to update user role: %v", err)
+	}
+
+	return &simplebankpb.UpdateUserRoleResponse{
+		User: convertUser(user),
+	}, nil
+}
+
+// BlockUserSessions logs out any user everywhere, access tokens already issued keep working until they expire.
+func (s *GRPCServer) BlockUserSessions(ctx context.Context, req *simplebankpb.BlockUserSessionsRequest) (*simplebankpb.BlockUserSessionsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "BlockUserSessionsRequest is empty")
+	}
+
+	if err := isBlockUserSessionsReqValid(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.store.GetUser(ctx, req.GetUsername()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user [%s] not found", req.GetUsername())
+		}
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	revoked, err := s.store.BlockUserSessions(ctx, req.GetUsername())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to block sessions: %v", err)
+	}
+
+	return &simplebankpb.BlockUserSessionsResponse{
+		Revoked: revoked,
+	}, nil
+}
+
+// LookupAccount returns any account, whether the user is a member of it or not.
+func (s *GRPCServer) LookupAccount(ctx context.Context, req *simplebankpb.LookupAccountRequest) (*simplebankpb.LookupAccountResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "LookupAccountRequest is empty")
+	}
+
+	if err := isLookupAccountReqValid(req); err != nil {
+		return nil, err
+	}
+
+	account, err := s.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "account [%d] not found", req.GetAccountId())
+		}
+		return nil, status.Errorf(codes.Internal, "unable to get account: %v", err)
+	}
+
+	return &simplebankpb.LookupAccountResponse{
+		Account: convertAccount(account),
+	}, nil
+}
+
+// FreezeAccount freezes any account, it can't send or receive transfers until it is unfrozen.
+func (s *GRPCServer) FreezeAccount(ctx context.Context, req *simplebankpb.FreezeAccountRequest) (*simplebankpb.FreezeAccountResponse, error) {
+	return s.changeAccountStatus(ctx, req, simplebanksql.AccountStatusFrozen)
+}
+
+// UnfreezeAccount reactivates a frozen account.
+func (s *GRPCServer) UnfreezeAccount(ctx context.Context, req *simplebankpb.FreezeAccountRequest) (*simplebankpb.FreezeAccountResponse, error) {
+	return s.changeAccountStatus(ctx, req, simplebanksql.AccountStatusActive)
+}
+
+// changeAccountStatus moves any account to the status as an operator, the change is recorded with the
+// username of the staff member.
+func (s *GRPCServer) changeAccountStatus(ctx context.Context, req *simplebankpb.FreezeAccountRequest, accountStatus simplebanksql.AccountStatus) (*simplebankpb.FreezeAccountResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "FreezeAccountRequest is empty")
+	}
+
+	if err := isFreezeAccountReqValid(req); err != nil {
+		return nil, err
+	}
+
+	payload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.store.ChangeAccountStatusTx(ctx, store.ChangeAccountStatusTxParams{
+		AccountID: req.GetAccountId(),
+		Status:    accountStatus,
+		ChangedBy: payload.Username,
+		Reason:    req.GetReason(),
+		Operator:  true,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, status.Errorf(codes.NotFound, "account [%d] not found", req.GetAccountId())
+		case errors.Is(err, store.ErrInvalidStatusTransition):
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "unable to change account status: %v", err)
+	}
+
+	return &simplebankpb.FreezeAccountResponse{
+		Account: convertAccount(result.Account),
+	}, nil
+}
+
+// Deposit credits any account with funds coming from outside the bank, it is posted by the staff once the funds arrived.
+func (s *GRPCServer) Deposit(ctx context.Context, req *simplebankpb.DepositRequest) (*simplebankpb.DepositResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "DepositRequest is empty")
+	}
+
+	if err := isDepositReqValid(req); err != nil {
+		return nil, err
+	}
+
+	result, err := s.settle(ctx, s.store.DepositTx, store.SettlementTxParams{
+		AccountID:         req.GetAccountId(),
+		Amount:            req.GetAmount(),
+		ExternalReference: req.GetExternalReference(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &simplebankpb.DepositResponse{
+		Transfer: convertTransfer(result.Transfer),
+		Account:  convertAccount(result.ToAccount),
+		Entry:    convertEntry(result.ToEntry),
+	}, nil
+}
+
+// Withdraw debits any account with funds leaving the bank, it is posted by the staff.
+func (s *GRPCServer) Withdraw(ctx context.Context, req *simplebankpb.WithdrawRequest) (*simplebankpb.WithdrawResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "WithdrawRequest is empty")
+	}
+
+	if err := isWithdrawReqValid(req); err != nil {
+		return nil, err
+	}
+
+	result, err := s.settle(ctx, s.store.WithdrawalTx, store.SettlementTxParams{
+		AccountID:         req.GetAccountId(),
+		Amount:            req.GetAmount(),
+		ExternalReference: req.GetExternalReference(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &simplebankpb.WithdrawResponse{
+		Transfer: convertTransfer(result.Transfer),
+		Account:  convertAccount(result.FromAccount),
+		Entry:    convertEntry(result.FromEntry),
+	}, nil
+}
+
+// settle posts a deposit or a withdrawal against the system accounts of the account's currency.
+// Settlements are operations of the bank, they don't count towards the spending limits of the members.
+func (s *GRPCServer) settle(ctx context.Context, settleTx func(context.Context, store.SettlementTxParams) (store.TransferTxResult, error), arg store.SettlementTxParams) (store.TransferTxResult, error) {
+	result, err := settleTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return result, status.Errorf(codes.NotFound, "account [%d] not found", arg.AccountID)
+		case errors.Is(err, store.ErrDuplicateReference):
+			return result, status.Errorf(codes.AlreadyExists, "%v", err)
+		case errors.Is(err, store.ErrInsufficientFunds), errors.Is(err, store.ErrAccountNotActive):
+			return result, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return result, status.Errorf(codes.Internal, "unable to settle account: %v", err)
+	}
+
+	return result, nil
+}
+
+func isSearchUsersReqValid(req *simplebankpb.SearchUsersRequest) error {
+	searchUsersValidator := validations.NewSearchUsersValidator(req)
+	return validations.BuildErrDetails(searchUsersValidator, "SearchUsersRequest error")
+}
+
+func isUpdateUserRoleReqValid(req *simplebankpb.UpdateUserRoleRequest) error {
+	updateUserRoleValidator := validations.NewUpdateUserRoleValidator(req)
+	return validations.BuildErrDetails(updateUserRoleValidator, "UpdateUserRoleRequest error")
+}
+
+func isBlockUserSessionsReqValid(req *simplebankpb.BlockUserSessionsRequest) error {
+	blockUserSessionsValidator := validations.NewBlockUserSessionsValidator(req)
+	return validations.BuildErrDetails(blockUserSessionsValidator, "BlockUserSessionsRequest error")
+}
+
+func isLookupAccountReqValid(req *simplebankpb.LookupAccountRequest) error {
+	lookupAccountValidator := validations.NewLookupAccountValidator(req)
+	return validations.BuildErrDetails(lookupAccountValidator, "LookupAccountRequest error")
+}
+
+func isFreezeAccountReqValid(req *simplebankpb.FreezeAccountRequest) error {
+	freezeAccountValidator := validations.NewFreezeAccountValidator(req)
+	return validations.BuildErrDetails(freezeAccountValidator, "FreezeAccountRequest error")
+}
+
+func isDepositReqValid(req *simplebankpb.DepositRequest) error {
+	depositValidator := validations.NewDepositValidator(req)
+	return validations.BuildErrDetails(depositValidator, "DepositRequest error")
+}
+
+func isWithdrawReqValid(req *simplebankpb.WithdrawRequest) error {
+	withdrawValidator := validations.NewWithdrawValidator(req)
+	return validations.BuildErrDetails(withdrawValidator, "WithdrawRequest error")
+}
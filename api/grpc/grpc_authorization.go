@@ -4,6 +4,8 @@ import (
 	"strings"
 
 	simplebankpb "github.com/orlandorode97/simple-bank/generated/simplebank"
+	"github.com/orlandorode97/simple-bank/pkg/policy"
+	"github.com/orlandorode97/simple-bank/pkg/token"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -14,11 +16,73 @@ import (
 const (
 	metadataAuthorizationHeader = "authorization"
 
-	updateUserRPC = "/simplebank.SimplebankService/UpdateUser"
+	updateUserRPC        = "/simplebank.SimplebankService/UpdateUser"
+	createTransferRPC    = "/simplebank.SimplebankService/CreateTransfer"
+	reverseTransferRPC   = "/simplebank.SimplebankService/ReverseTransfer"
+	authorizeTransferRPC = "/simplebank.SimplebankService/AuthorizeTransfer"
+	captureTransferRPC   = "/simplebank.SimplebankService/CaptureTransfer"
+	voidTransferRPC      = "/simplebank.SimplebankService/VoidTransfer"
+	batchTransferRPC     = "/simplebank.SimplebankService/BatchTransfer"
+	closeAccountRPC      = "/simplebank.SimplebankService/CloseAccount"
+	depositRPC           = "/simplebank.SimplebankService/Deposit"
+	withdrawRPC          = "/simplebank.SimplebankService/Withdraw"
+	movePocketFundsRPC   = "/simplebank.SimplebankService/MovePocketFunds"
+	listSessionsRPC      = "/simplebank.SimplebankService/ListSessions"
+	revokeSessionRPC     = "/simplebank.SimplebankService/RevokeSession"
+	revokeAllSessionsRPC = "/simplebank.SimplebankService/RevokeAllSessions"
+	searchUsersRPC       = "/simplebank.SimplebankService/SearchUsers"
+	updateUserRoleRPC    = "/simplebank.SimplebankService/UpdateUserRole"
+	blockUserSessionsRPC = "/simplebank.SimplebankService/BlockUserSessions"
+	lookupAccountRPC     = "/simplebank.SimplebankService/LookupAccount"
+	freezeAccountRPC     = "/simplebank.SimplebankService/FreezeAccount"
+	unfreezeAccountRPC   = "/simplebank.SimplebankService/UnfreezeAccount"
 )
 
 var protectedRPCs = map[string]bool{
-	updateUserRPC: true,
+	updateUserRPC:        true,
+	createTransferRPC:    true,
+	reverseTransferRPC:   true,
+	authorizeTransferRPC: true,
+	captureTransferRPC:   true,
+	voidTransferRPC:      true,
+	batchTransferRPC:     true,
+	closeAccountRPC:      true,
+	depositRPC:           true,
+	withdrawRPC:          true,
+	movePocketFundsRPC:   true,
+	listSessionsRPC:      true,
+	revokeSessionRPC:     true,
+	revokeAllSessionsRPC: true,
+	searchUsersRPC:       true,
+	updateUserRoleRPC:    true,
+	blockUserSessionsRPC: true,
+	lookupAccountRPC:     true,
+	freezeAccountRPC:     true,
+	unfreezeAccountRPC:   true,
+}
+
+// rpcPermissions stores the permission the role of the user must have to call the staff RPCs.
+var rpcPermissions = map[string]policy.Permission{
+	searchUsersRPC:       policy.PermissionSearchUsers,
+	updateUserRoleRPC:    policy.PermissionManageRoles,
+	blockUserSessionsRPC: policy.PermissionBlockSessions,
+	lookupAccountRPC:     policy.PermissionViewAccounts,
+	freezeAccountRPC:     policy.PermissionFreezeAccounts,
+	unfreezeAccountRPC:   policy.PermissionFreezeAccounts,
+	depositRPC:           policy.PermissionPostSettlements,
+	withdrawRPC:          policy.PermissionPostSettlements,
+}
+
+type authorizationPayloadKey struct{}
+
+// payloadFromContext returns the token payload set by the AuthInterceptor for protected RPCs.
+func payloadFromContext(ctx context.Context) (*token.Payload, error) {
+	payload, ok := ctx.Value(authorizationPayloadKey{}).(*token.Payload)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization payload not found")
+	}
+
+	return payload, nil
 }
 
 func (s *GRPCServer) AuthInterceptor() grpc.UnaryServerInterceptor {
@@ -45,6 +109,12 @@ func (s *GRPCServer) AuthInterceptor() grpc.UnaryServerInterceptor {
 				return nil, status.Errorf(codes.Unauthenticated, "%v", err)
 			}
 
+			if permission, ok := rpcPermissions[info.FullMethod]; ok {
+				if err := policy.Authorize(payload.Role, permission); err != nil {
+					return nil, status.Errorf(codes.PermissionDenied, "%v", err)
+				}
+			}
+
 			switch info.FullMethod {
 			case updateUserRPC:
 				updateUserRequest, _ := req.(*simplebankpb.UpdateUserRequest)
@@ -52,6 +122,8 @@ func (s *GRPCServer) AuthInterceptor() grpc.UnaryServerInterceptor {
 					return nil, status.Errorf(codes.PermissionDenied, "unable to update user information")
 				}
 			}
+
+			ctx = context.WithValue(ctx, authorizationPayloadKey{}, payload)
 		}
 
 		return handler(ctx, req)
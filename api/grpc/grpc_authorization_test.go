@@ -30,7 +30,12 @@ func TestUnaryInterceptor(t *testing.T) {
 		tokenMaker: tokenMaker,
 	}
 
-	accessToken, _, err := server.tokenMaker.CreateToken("orlandorode97", 1*time.Minute)
+	accessToken, _, err := server.tokenMaker.CreateToken("orlandorode97", "customer", 1*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tellerToken, _, err := server.tokenMaker.CreateToken("juanito97", "teller", 1*time.Minute)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -77,6 +82,32 @@ func TestUnaryInterceptor(t *testing.T) {
 
 			wantGRPCCode: codes.OK,
 		},
+		{
+			desc: "success - token payload is available to protected RPC handlers",
+			req: &simplebank.CreateTransferRequest{
+				FromAccountId: 1,
+			},
+			info: &grpc.UnaryServerInfo{
+				FullMethod: createTransferRPC,
+			},
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				payload, err := payloadFromContext(ctx)
+				if err != nil {
+					return nil, err
+				}
+				if payload.Username != "orlandorode97" {
+					return nil, status.Errorf(codes.Internal, "unexpected payload username %s", payload.Username)
+				}
+				return nil, nil
+			},
+			metadata: metadata.MD{
+				metadataAuthorizationHeader: []string{
+					"Bearer " + accessToken,
+				},
+			},
+
+			wantGRPCCode: codes.OK,
+		},
 		{
 			desc: "failure - metadata not provided",
 			req: &simplebank.UpdateUserRequest{
@@ -169,6 +200,133 @@ func TestUnaryInterceptor(t *testing.T) {
 
 			wantGRPCCode: codes.PermissionDenied,
 		},
+		{
+			desc: "success - role has the permission of the staff RPC",
+			req: &simplebank.SearchUsersRequest{
+				Query: "orlando",
+			},
+			info: &grpc.UnaryServerInfo{
+				FullMethod: searchUsersRPC,
+			},
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+
+			metadata: metadata.MD{
+				metadataAuthorizationHeader: []string{
+					"Bearer " + tellerToken,
+				},
+			},
+
+			wantGRPCCode: codes.OK,
+		},
+		{
+			desc: "failure - customer calls a staff RPC",
+			req: &simplebank.SearchUsersRequest{
+				Query: "orlando",
+			},
+			info: &grpc.UnaryServerInfo{
+				FullMethod: searchUsersRPC,
+			},
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+
+			metadata: metadata.MD{
+				metadataAuthorizationHeader: []string{
+					"Bearer " + accessToken,
+				},
+			},
+
+			wantGRPCCode: codes.PermissionDenied,
+		},
+		{
+			desc: "failure - role lacks the permission of the staff RPC",
+			req: &simplebank.FreezeAccountRequest{
+				AccountId: 1,
+				Reason:    "fraud",
+			},
+			info: &grpc.UnaryServerInfo{
+				FullMethod: freezeAccountRPC,
+			},
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+
+			metadata: metadata.MD{
+				metadataAuthorizationHeader: []string{
+					"Bearer " + tellerToken,
+				},
+			},
+
+			wantGRPCCode: codes.PermissionDenied,
+		},
+		{
+			desc: "failure - customer posts a deposit",
+			req: &simplebank.DepositRequest{
+				AccountId:         1,
+				Amount:            100,
+				ExternalReference: "wire-1",
+			},
+			info: &grpc.UnaryServerInfo{
+				FullMethod: depositRPC,
+			},
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+
+			metadata: metadata.MD{
+				metadataAuthorizationHeader: []string{
+					"Bearer " + accessToken,
+				},
+			},
+
+			wantGRPCCode: codes.PermissionDenied,
+		},
+		{
+			desc: "failure - customer posts a withdrawal",
+			req: &simplebank.WithdrawRequest{
+				AccountId:         1,
+				Amount:            100,
+				ExternalReference: "wire-2",
+			},
+			info: &grpc.UnaryServerInfo{
+				FullMethod: withdrawRPC,
+			},
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+
+			metadata: metadata.MD{
+				metadataAuthorizationHeader: []string{
+					"Bearer " + accessToken,
+				},
+			},
+
+			wantGRPCCode: codes.PermissionDenied,
+		},
+		{
+			desc: "success - teller posts a deposit",
+			req: &simplebank.DepositRequest{
+				AccountId:         1,
+				Amount:            100,
+				ExternalReference: "wire-3",
+			},
+			info: &grpc.UnaryServerInfo{
+				FullMethod: depositRPC,
+			},
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+
+			metadata: metadata.MD{
+				metadataAuthorizationHeader: []string{
+					"Bearer " + tellerToken,
+				},
+			},
+
+			wantGRPCCode: codes.OK,
+		},
 	}
 
 	for _, tc := range tcs {
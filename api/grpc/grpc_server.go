@@ -19,7 +19,14 @@ type GRPCServer struct {
 }
 
 func NewServer(conf config.Config, store store.Store, logger *zap.SugaredLogger, taskDistributor workers.TaskDistributor) (*GRPCServer, error) {
-	tokenMaker, err := token.NewPasetoMaker(conf.SymmetricKey)
+	tokenMaker, err := token.NewMaker(token.Config{
+		Kind:         conf.TokenMaker,
+		SymmetricKey: conf.SymmetricKey,
+		PrivateKey:   conf.TokenPrivateKey,
+		KeyringFile:  conf.TokenKeyringFile,
+		Keyring:      conf.TokenKeyring,
+		GracePeriod:  conf.TokenKeyGracePeriod,
+	})
 	if err != nil {
 		return nil, err
 	}
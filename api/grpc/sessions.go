@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	simplebankpb "github.com/orlandorode97/simple-bank/generated/simplebank"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/validations"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListSessions lists the sessions of the authenticated user that are neither revoked nor expired, newest first.
+func (s *GRPCServer) ListSessions(ctx context.Context, req *simplebankpb.ListSessionsRequest) (*simplebankpb.ListSessionsResponse, error) {
+	payload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.store.ListActiveSessions(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to list sessions: %v", err)
+	}
+
+	resp := &simplebankpb.ListSessionsResponse{
+		Sessions: make([]*simplebankpb.Session, 0, len(sessions)),
+	}
+
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, convertSession(session))
+	}
+
+	return resp, nil
+}
+
+// RevokeSession logs out a session of the authenticated user, its refresh token can't be used anymore.
+func (s *GRPCServer) RevokeSession(ctx context.Context, req *simplebankpb.RevokeSessionRequest) (*simplebankpb.RevokeSessionResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "RevokeSessionRequest is empty")
+	}
+
+	if err := isRevokeSessionReqValid(req); err != nil {
+		return nil, err
+	}
+
+	payload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.store.BlockSession(ctx, simplebanksql.BlockSessionParams{
+		ID:       uuid.MustParse(req.GetSessionId()),
+		Username: payload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "session [%s] not found", req.GetSessionId())
+		}
+		return nil, status.Errorf(codes.Internal, "unable to revoke session: %v", err)
+	}
+
+	return &simplebankpb.RevokeSessionResponse{
+		Session: convertSession(session),
+	}, nil
+}
+
+// RevokeAllSessions logs out the authenticated user everywhere, access tokens already issued keep working until they expire.
+func (s *GRPCServer) RevokeAllSessions(ctx context.Context, req *simplebankpb.RevokeAllSessionsRequest) (*simplebankpb.RevokeAllSessionsResponse, error) {
+	payload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.store.BlockUserSessions(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to revoke sessions: %v", err)
+	}
+
+	return &simplebankpb.RevokeAllSessionsResponse{
+		Revoked: revoked,
+	}, nil
+}
+
+func convertSession(session simplebanksql.Session) *simplebankpb.Session {
+	return &simplebankpb.Session{
+		Id:        session.ID.String(),
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		IsBlocked: session.IsBlocked,
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+		CreatedAt: timestamppb.New(session.CreateadAt),
+	}
+}
+
+func isRevokeSessionReqValid(req *simplebankpb.RevokeSessionRequest) error {
+	revokeSessionValidator := validations.NewRevokeSessionValidator(req)
+	return validations.BuildErrDetails(revokeSessionValidator, "RevokeSessionRequest error")
+}
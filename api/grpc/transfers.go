@@ -0,0 +1,577 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	simplebankpb "github.com/orlandorode97/simple-bank/generated/simplebank"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/access"
+	"github.com/orlandorode97/simple-bank/pkg/accountnumber"
+	"github.com/orlandorode97/simple-bank/pkg/validations"
+	"github.com/orlandorode97/simple-bank/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// metadataIdempotencyKey lets clients retry a transfer without moving money twice.
+const metadataIdempotencyKey = "idempotency-key"
+
+func (s *GRPCServer) CreateTransfer(ctx context.Context, req *simplebankpb.CreateTransferRequest) (*simplebankpb.CreateTransferResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "CreateTransferRequest is empty")
+	}
+
+	if err := isCreateTransferReqValid(req); err != nil {
+		return nil, err
+	}
+
+	arg := store.TransferTxParams{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+	}
+
+	if err := s.resolveAccountNumber(ctx, req.GetFromAccountNumber(), &arg.FromAccountID); err != nil {
+		return nil, err
+	}
+
+	if err := s.resolveAccountNumber(ctx, req.GetToAccountNumber(), &arg.ToAccountID); err != nil {
+		return nil, err
+	}
+
+	currency, err := s.enabledCurrency(ctx, req.GetCurrencyId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.transfer(ctx, arg, currency.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &simplebankpb.CreateTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+		Fee:         convertFee(result.Fee),
+	}, nil
+}
+
+// AuthorizeTransfer holds the amount on the from account, the transfer is settled once it is captured.
+func (s *GRPCServer) AuthorizeTransfer(ctx context.Context, req *simplebankpb.AuthorizeTransferRequest) (*simplebankpb.AuthorizeTransferResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "AuthorizeTransferRequest is empty")
+	}
+
+	if err := isAuthorizeTransferReqValid(req); err != nil {
+		return nil, err
+	}
+
+	arg := store.TransferTxParams{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+		HoldDuration:  s.config.HoldDuration,
+	}
+
+	if err := s.resolveAccountNumber(ctx, req.GetFromAccountNumber(), &arg.FromAccountID); err != nil {
+		return nil, err
+	}
+
+	if err := s.resolveAccountNumber(ctx, req.GetToAccountNumber(), &arg.ToAccountID); err != nil {
+		return nil, err
+	}
+
+	currency, err := s.enabledCurrency(ctx, req.GetCurrencyId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetExpiresIn() != 0 {
+		arg.HoldDuration = time.Duration(req.GetExpiresIn()) * time.Second
+	}
+
+	result, err := s.transfer(ctx, arg, currency.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &simplebankpb.AuthorizeTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		Hold:        convertHold(*result.Hold),
+	}, nil
+}
+
+// transfer moves the amount between the accounts, or only holds it when a hold duration is set.
+func (s *GRPCServer) transfer(ctx context.Context, arg store.TransferTxParams, currencyID int64) (store.TransferTxResult, error) {
+	payload, err := payloadFromContext(ctx)
+	if err != nil {
+		return store.TransferTxResult{}, err
+	}
+
+	if _, err := s.validAccount(ctx, arg.FromAccountID, currencyID, arg.Amount); err != nil {
+		return store.TransferTxResult{}, err
+	}
+	arg.InitiatedBy = payload.Username
+
+	// The to account may hold a different currency, the amount is converted with the exchange rate in effect.
+	if _, err := s.findAccount(ctx, arg.ToAccountID); err != nil {
+		return store.TransferTxResult{}, err
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(metadataIdempotencyKey); len(keys) != 0 && keys[0] != "" {
+			arg.Idempotency = &store.IdempotencyParams{
+				Key:      keys[0],
+				Username: payload.Username,
+				Duration: s.config.IdempotencyKeyDuration,
+			}
+		}
+	}
+
+	result, err := s.store.TransferTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrSameAccount):
+			return result, status.Errorf(codes.InvalidArgument, "%v", err)
+		case errors.Is(err, store.ErrIdempotencyKeyConflict):
+			return result, status.Errorf(codes.AlreadyExists, "%v", err)
+		case errors.Is(err, store.ErrExchangeRateNotFound),
+			errors.Is(err, store.ErrInsufficientFunds),
+			errors.Is(err, store.ErrAccountNotActive),
+			errors.Is(err, store.ErrSpendingLimitExceeded):
+			return result, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return result, status.Errorf(codes.Internal, "unable to create transfer: %v", err)
+	}
+
+	return result, nil
+}
+
+// ReverseTransfer refunds a transfer fully or partially, only a member of the account that received it allowed to spend can do it.
+func (s *GRPCServer) ReverseTransfer(ctx context.Context, req *simplebankpb.ReverseTransferRequest) (*simplebankpb.ReverseTransferResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "ReverseTransferRequest is empty")
+	}
+
+	if err := isReverseTransferReqValid(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.receivedTransfer(ctx, req.GetTransferId(), access.ActionSpend, req.GetAmount()); err != nil {
+		return nil, err
+	}
+
+	result, err := s.store.ReverseTransferTx(ctx, store.ReverseTransferTxParams{
+		TransferID: req.GetTransferId(),
+		Amount:     req.GetAmount(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrTransferNotReversible),
+			errors.Is(err, store.ErrRefundExceedsTransfer),
+			errors.Is(err, store.ErrInsufficientFunds),
+			errors.Is(err, store.ErrAccountNotActive):
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "unable to reverse transfer: %v", err)
+	}
+
+	return &simplebankpb.ReverseTransferResponse{
+		Transfer:         convertTransfer(result.Transfer),
+		OriginalTransfer: convertTransfer(result.OriginalTransfer),
+		FromAccount:      convertAccount(result.FromAccount),
+		ToAccount:        convertAccount(result.ToAccount),
+		FromEntry:        convertEntry(result.FromEntry),
+		ToEntry:          convertEntry(result.ToEntry),
+		RefundedAmount:   result.RefundedAmount,
+	}, nil
+}
+
+// CaptureTransfer settles an authorized transfer fully or partially, only a member of the account that receives it can do it.
+func (s *GRPCServer) CaptureTransfer(ctx context.Context, req *simplebankpb.CaptureTransferRequest) (*simplebankpb.CaptureTransferResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "CaptureTransferRequest is empty")
+	}
+
+	if err := isCaptureTransferReqValid(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.receivedTransfer(ctx, req.GetTransferId(), access.ActionReceive, 0); err != nil {
+		return nil, err
+	}
+
+	result, err := s.store.CaptureTransferTx(ctx, store.CaptureTransferTxParams{
+		TransferID: req.GetTransferId(),
+		Amount:     req.GetAmount(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrTransferNotPending),
+			errors.Is(err, store.ErrHoldExpired),
+			errors.Is(err, store.ErrCaptureExceedsHold),
+			errors.Is(err, store.ErrInsufficientFunds),
+			errors.Is(err, store.ErrAccountNotActive):
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "unable to capture transfer: %v", err)
+	}
+
+	return &simplebankpb.CaptureTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+		Hold:        convertHold(*result.Hold),
+		Fee:         convertFee(result.Fee),
+	}, nil
+}
+
+// VoidTransfer cancels an authorized transfer and releases its hold, only a member of the account that receives it can do it.
+func (s *GRPCServer) VoidTransfer(ctx context.Context, req *simplebankpb.VoidTransferRequest) (*simplebankpb.VoidTransferResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "VoidTransferRequest is empty")
+	}
+
+	if err := isVoidTransferReqValid(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.receivedTransfer(ctx, req.GetTransferId(), access.ActionReceive, 0); err != nil {
+		return nil, err
+	}
+
+	result, err := s.store.VoidTransferTx(ctx, req.GetTransferId())
+	if err != nil {
+		if errors.Is(err, store.ErrTransferNotPending) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "unable to void transfer: %v", err)
+	}
+
+	return &simplebankpb.VoidTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		Hold:        convertHold(result.Hold),
+	}, nil
+}
+
+// BatchTransfer performs every leg from the same account within a single transaction, either all legs
+// are posted or none of them.
+func (s *GRPCServer) BatchTransfer(ctx context.Context, req *simplebankpb.BatchTransferRequest) (*simplebankpb.BatchTransferResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "BatchTransferRequest is empty")
+	}
+
+	if err := isBatchTransferReqValid(req); err != nil {
+		return nil, err
+	}
+
+	payload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fromAccountID := req.GetFromAccountId()
+	if err := s.resolveAccountNumber(ctx, req.GetFromAccountNumber(), &fromAccountID); err != nil {
+		return nil, err
+	}
+
+	currency, err := s.enabledCurrency(ctx, req.GetCurrencyId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	legs := make([]store.BatchTransferLeg, 0, len(req.GetLegs()))
+	for _, leg := range req.GetLegs() {
+		toAccountID := leg.GetToAccountId()
+		if err := s.resolveAccountNumber(ctx, leg.GetToAccountNumber(), &toAccountID); err != nil {
+			return nil, err
+		}
+
+		legs = append(legs, store.BatchTransferLeg{
+			ToAccountID: toAccountID,
+			Amount:      leg.GetAmount(),
+		})
+	}
+
+	// The spend limit of a member applies to the whole batch.
+	var total int64
+	for _, leg := range req.GetLegs() {
+		if total > math.MaxInt64-leg.GetAmount() {
+			total = math.MaxInt64
+			break
+		}
+		total += leg.GetAmount()
+	}
+
+	if _, err := s.validAccount(ctx, fromAccountID, currency.ID, total); err != nil {
+		return nil, err
+	}
+
+	arg := store.BatchTransferTxParams{
+		FromAccountID: fromAccountID,
+		Legs:          legs,
+		InitiatedBy:   payload.Username,
+	}
+
+	result, err := s.store.BatchTransferTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, status.Errorf(codes.NotFound, "%v", err)
+		case errors.Is(err, store.ErrInvalidBatchLeg):
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		case errors.Is(err, store.ErrExchangeRateNotFound),
+			errors.Is(err, store.ErrInsufficientFunds),
+			errors.Is(err, store.ErrAccountNotActive),
+			errors.Is(err, store.ErrSpendingLimitExceeded):
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "unable to create batch transfer: %v", err)
+	}
+
+	results := make([]*simplebankpb.CreateTransferResponse, 0, len(result.Legs))
+	for _, leg := range result.Legs {
+		results = append(results, &simplebankpb.CreateTransferResponse{
+			Transfer:    convertTransfer(leg.Transfer),
+			FromAccount: convertAccount(leg.FromAccount),
+			ToAccount:   convertAccount(leg.ToAccount),
+			FromEntry:   convertEntry(leg.FromEntry),
+			ToEntry:     convertEntry(leg.ToEntry),
+			Fee:         convertFee(leg.Fee),
+		})
+	}
+
+	return &simplebankpb.BatchTransferResponse{
+		FromAccount: convertAccount(result.FromAccount),
+		TotalAmount: result.TotalAmount,
+		TotalFees:   result.TotalFees,
+		Legs:        results,
+	}, nil
+}
+
+// receivedTransfer valids the transfer exists and was received by an account the authenticated user is allowed
+// to do the action with, an amount of zero stands for the whole transfer amount.
+func (s *GRPCServer) receivedTransfer(ctx context.Context, transferID int64, action access.Action, amount int64) (simplebanksql.Transfer, error) {
+	transfer, err := s.store.GetTransfer(ctx, transferID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return transfer, status.Errorf(codes.NotFound, "transfer [%d] not found", transferID)
+		}
+		return transfer, status.Errorf(codes.Internal, "unable to get transfer: %v", err)
+	}
+
+	if amount == 0 {
+		amount = transfer.ToAmount
+	}
+
+	if _, err := s.authorizedAccount(ctx, transfer.ToAccountID, action, amount); err != nil {
+		return transfer, err
+	}
+
+	return transfer, nil
+}
+
+// validAccount valids the account exists, the account's currency, that the account is active to send funds,
+// and that the authenticated user is a member allowed to spend amount.
+func (s *GRPCServer) validAccount(ctx context.Context, accountID int64, currencyID int64, amount int64) (simplebanksql.Account, error) {
+	account, err := s.authorizedAccount(ctx, accountID, access.ActionSpend, amount)
+	if err != nil {
+		return account, err
+	}
+
+	if account.Status != simplebanksql.AccountStatusActive {
+		return account, status.Errorf(codes.FailedPrecondition, "%v: account [%d] is %s", store.ErrAccountNotActive, accountID, account.Status)
+	}
+
+	if account.CurrencyID != currencyID {
+		return account, status.Errorf(codes.InvalidArgument, "account [%d] currency mismatch %v - %v", accountID, account.CurrencyID, currencyID)
+	}
+
+	return account, nil
+}
+
+// findAccount valids the account exists.
+func (s *GRPCServer) findAccount(ctx context.Context, accountID int64) (simplebanksql.Account, error) {
+	account, err := s.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found", accountID)
+		}
+		return account, status.Errorf(codes.Internal, "unable to get account: %v", err)
+	}
+
+	return account, nil
+}
+
+// enabledCurrency valids the currency is an enabled currency of the catalog, the code is used instead of the id
+// when it is set.
+func (s *GRPCServer) enabledCurrency(ctx context.Context, currencyID int64, code string) (simplebanksql.Currency, error) {
+	currency, err := s.store.EnabledCurrency(ctx, store.EnabledCurrencyParams{ID: currencyID, Code: code})
+	if err != nil {
+		if errors.Is(err, store.ErrCurrencyNotFound) || errors.Is(err, store.ErrCurrencyDisabled) {
+			return currency, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		return currency, status.Errorf(codes.Internal, "unable to get currency: %v", err)
+	}
+
+	return currency, nil
+}
+
+// resolveAccountNumber sets accountID to the id of the account with the account number, nothing is done when the
+// number is empty. The check digits are validated before the account is looked up.
+func (s *GRPCServer) resolveAccountNumber(ctx context.Context, number string, accountID *int64) error {
+	if number == "" {
+		return nil
+	}
+
+	number = accountnumber.Normalize(number)
+	if err := accountnumber.Validate(number); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	account, err := s.store.GetAccountByNumber(ctx, number)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Errorf(codes.NotFound, "account number %s not found", number)
+		}
+		return status.Errorf(codes.Internal, "unable to get account: %v", err)
+	}
+
+	if *accountID != 0 && *accountID != account.ID {
+		return status.Errorf(codes.InvalidArgument, "account number %s does not belong to account [%d]", number, *accountID)
+	}
+
+	*accountID = account.ID
+
+	return nil
+}
+
+func convertAccount(account simplebanksql.Account) *simplebankpb.Account {
+	return &simplebankpb.Account{
+		Id:             account.ID,
+		Owner:          account.Owner,
+		Balance:        account.Balance,
+		CurrencyId:     account.CurrencyID,
+		CreatedAt:      timestamppb.New(account.CreateadAt),
+		OverdraftLimit: account.OverdraftLimit,
+		HeldBalance:    account.HeldBalance,
+		Status:         string(account.Status),
+		Name:           account.Name,
+		AccountNumber:  account.AccountNumber,
+	}
+}
+
+func convertTransfer(transfer simplebanksql.Transfer) *simplebankpb.Transfer {
+	var reversedTransferID *int64
+	if transfer.ReversedTransferID.Valid {
+		reversedTransferID = &transfer.ReversedTransferID.Int64
+	}
+
+	var externalReference *string
+	if transfer.ExternalReference.Valid {
+		externalReference = &transfer.ExternalReference.String
+	}
+
+	var chargedTransferID *int64
+	if transfer.ChargedTransferID.Valid {
+		chargedTransferID = &transfer.ChargedTransferID.Int64
+	}
+
+	return &simplebankpb.Transfer{
+		Id:                 transfer.ID,
+		FromAccountId:      transfer.FromAccountID,
+		ToAccountId:        transfer.ToAccountID,
+		Amount:             transfer.Amount,
+		ToAmount:           transfer.ToAmount,
+		ExchangeRate:       transfer.ExchangeRate,
+		ReversedTransferId: reversedTransferID,
+		Status:             string(transfer.Status),
+		ExternalReference:  externalReference,
+		ChargedTransferId:  chargedTransferID,
+		CreatedAt:          timestamppb.New(transfer.CreateadAt),
+	}
+}
+
+func convertEntry(entry simplebanksql.Entry) *simplebankpb.Entry {
+	return &simplebankpb.Entry{
+		Id:        entry.ID,
+		AccountId: entry.AccountID,
+		Amount:    entry.Amount,
+		CreatedAt: timestamppb.New(entry.CreateadAt),
+	}
+}
+
+func convertHold(hold simplebanksql.Hold) *simplebankpb.Hold {
+	var releasedAt *timestamppb.Timestamp
+	if hold.ReleasedAt.Valid {
+		releasedAt = timestamppb.New(hold.ReleasedAt.Time)
+	}
+
+	return &simplebankpb.Hold{
+		Id:         hold.ID,
+		TransferId: hold.TransferID,
+		AccountId:  hold.AccountID,
+		Amount:     hold.Amount,
+		ExpiresAt:  timestamppb.New(hold.ExpiresAt),
+		ReleasedAt: releasedAt,
+		CreatedAt:  timestamppb.New(hold.CreateadAt),
+	}
+}
+
+// convertFee returns nil when no fee was charged.
+func convertFee(fee *store.TransferFee) *simplebankpb.Fee {
+	if fee == nil {
+		return nil
+	}
+
+	return &simplebankpb.Fee{
+		ScheduleId: fee.ScheduleID,
+		Amount:     fee.Amount,
+		Transfer:   convertTransfer(fee.Transfer),
+		Entry:      convertEntry(fee.Entry),
+	}
+}
+
+func isCreateTransferReqValid(req *simplebankpb.CreateTransferRequest) error {
+	createTransferValidator := validations.NewCreateTransferValidator(req)
+	return validations.BuildErrDetails(createTransferValidator, "CreateTransferRequest error")
+}
+
+func isAuthorizeTransferReqValid(req *simplebankpb.AuthorizeTransferRequest) error {
+	authorizeTransferValidator := validations.NewAuthorizeTransferValidator(req)
+	return validations.BuildErrDetails(authorizeTransferValidator, "AuthorizeTransferRequest error")
+}
+
+func isCaptureTransferReqValid(req *simplebankpb.CaptureTransferRequest) error {
+	captureTransferValidator := validations.NewCaptureTransferValidator(req)
+	return validations.BuildErrDetails(captureTransferValidator, "CaptureTransferRequest error")
+}
+
+func isVoidTransferReqValid(req *simplebankpb.VoidTransferRequest) error {
+	voidTransferValidator := validations.NewVoidTransferValidator(req)
+	return validations.BuildErrDetails(voidTransferValidator, "VoidTransferRequest error")
+}
+
+func isReverseTransferReqValid(req *simplebankpb.ReverseTransferRequest) error {
+	reverseTransferValidator := validations.NewReverseTransferValidator(req)
+	return validations.BuildErrDetails(reverseTransferValidator, "ReverseTransferRequest error")
+}
+
+func isBatchTransferReqValid(req *simplebankpb.BatchTransferRequest) error {
+	batchTransferValidator := validations.NewBatchTransferValidator(req)
+	return validations.BuildErrDetails(batchTransferValidator, "BatchTransferRequest error")
+}
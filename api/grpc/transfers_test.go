@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"testing"
+
+	simplebankpb "github.com/orlandorode97/simple-bank/generated/simplebank"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateTransferReqValidSameAccount(t *testing.T) {
+	tcs := []struct {
+		desc string
+		req  *simplebankpb.CreateTransferRequest
+
+		wantGRPCCode codes.Code
+	}{
+		{
+			desc: "success - different accounts",
+			req: &simplebankpb.CreateTransferRequest{
+				FromAccountId: 1,
+				ToAccountId:   2,
+				Amount:        100,
+				Currency:      "USD",
+			},
+			wantGRPCCode: codes.OK,
+		},
+		{
+			desc: "failure - same account ids",
+			req: &simplebankpb.CreateTransferRequest{
+				FromAccountId: 1,
+				ToAccountId:   1,
+				Amount:        100,
+				Currency:      "USD",
+			},
+			wantGRPCCode: codes.InvalidArgument,
+		},
+		{
+			desc: "failure - same account numbers",
+			req: &simplebankpb.CreateTransferRequest{
+				FromAccountNumber: "SB0000000001",
+				ToAccountNumber:   "SB0000000001",
+				Amount:            100,
+				Currency:          "USD",
+			},
+			wantGRPCCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := isCreateTransferReqValid(tc.req)
+			if got := status.Code(err); got != tc.wantGRPCCode {
+				t.Errorf("response status: got %s want %s", got, tc.wantGRPCCode)
+			}
+		})
+	}
+}
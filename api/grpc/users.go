@@ -74,13 +74,7 @@ func (s *GRPCServer) CreateUser(ctx context.Context, req *simplebankpb.CreateUse
 	}
 
 	return &simplebankpb.CreateUserResponse{
-		User: &simplebankpb.User{
-			Username:          result.User.Username,
-			FullName:          result.User.FullName,
-			Email:             result.User.Email,
-			PasswordChangedAt: timestamppb.New(result.User.PasswordChangedAt),
-			CreatedAt:         timestamppb.New(result.User.CreateadAt),
-		},
+		User: convertUser(result.User),
 	}, nil
 }
 
@@ -104,12 +98,12 @@ func (s *GRPCServer) Login(ctx context.Context, req *simplebankpb.LoginRequest)
 		return nil, status.Error(codes.InvalidArgument, "incorrect username/password")
 	}
 
-	token, accessPayload, err := s.tokenMaker.CreateToken(user.Username, s.config.TokenDuration)
+	token, accessPayload, err := s.tokenMaker.CreateToken(user.Username, string(user.Role), s.config.TokenDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "unable to generate token: %v", err)
 	}
 
-	refreshToken, refreshPayload, err := s.tokenMaker.CreateToken(user.Username, s.config.TokenRefreshDuration)
+	refreshToken, refreshPayload, err := s.tokenMaker.CreateToken(user.Username, string(user.Role), s.config.TokenRefreshDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "unable to generate refresh token: %v", err)
 	}
@@ -137,6 +131,7 @@ func (s *GRPCServer) Login(ctx context.Context, req *simplebankpb.LoginRequest)
 		ClientIp:     peer.Addr.String(),
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiredAt,
+		FamilyID:     refreshPayload.ID,
 	})
 
 	if err != nil {
@@ -149,13 +144,7 @@ func (s *GRPCServer) Login(ctx context.Context, req *simplebankpb.LoginRequest)
 		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiredAt),
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiresAt: timestamppb.New(refreshPayload.ExpiredAt),
-		User: &simplebankpb.User{
-			Username:          user.Username,
-			FullName:          user.FullName,
-			Email:             user.Email,
-			PasswordChangedAt: timestamppb.New(user.PasswordChangedAt),
-			CreatedAt:         timestamppb.New(user.CreateadAt),
-		},
+		User:                  convertUser(user),
 	}, nil
 }
 
@@ -208,13 +197,7 @@ func (s *GRPCServer) UpdateUser(ctx context.Context, req *simplebankpb.UpdateUse
 	}
 
 	return &simplebankpb.UpdateUserResponse{
-		User: &simplebankpb.User{
-			Username:          updateUser.Username,
-			FullName:          updateUser.FullName,
-			Email:             updateUser.Email,
-			PasswordChangedAt: timestamppb.New(updateUser.PasswordChangedAt),
-			CreatedAt:         timestamppb.New(updateUser.CreateadAt),
-		},
+		User: convertUser(updateUser),
 	}, nil
 }
 
@@ -232,3 +215,14 @@ func isUpdateUserReqValid(req *simplebankpb.UpdateUserRequest) error {
 	updateUserValidator := validations.NewUpdateUserValidator(req)
 	return validations.BuildErrDetails(updateUserValidator, "UpdateUserRequest error")
 }
+
+func convertUser(user simplebanksql.User) *simplebankpb.User {
+	return &simplebankpb.User{
+		Username:          user.Username,
+		FullName:          user.FullName,
+		Email:             user.Email,
+		PasswordChangedAt: timestamppb.New(user.PasswordChangedAt),
+		CreatedAt:         timestamppb.New(user.CreateadAt),
+		Role:              string(user.Role),
+	}
+}
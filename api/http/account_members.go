@@ -0,0 +1,171 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/access"
+	"github.com/orlandorode97/simple-bank/pkg/token"
+	"github.com/orlandorode97/simple-bank/store"
+)
+
+func (s *Server) addInvitationRoutes(r *gin.RouterGroup) {
+	r.GET("/invitations", s.listInvitations)
+}
+
+type inviteAccountMemberRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Role     string `json:"role" binding:"required,oneof=owner full_access view_only spend_limit"`
+	// SpendLimit is the largest amount a spend_limit member can move in a single operation.
+	SpendLimit int64 `json:"spend_limit" binding:"required_if=Role spend_limit,omitempty,gt=0"`
+}
+
+// inviteAccountMember invites a user to an account the user manages, the invited user has no access until accepting.
+func (s *Server) inviteAccountMember(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req inviteAccountMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionManage, 0); !valid {
+		return
+	}
+
+	if _, err := s.store.GetUser(ctx, req.Username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	member, err := s.store.InviteAccountMemberTx(ctx, store.InviteAccountMemberTxParams{
+		AccountID:  uri.ID,
+		Username:   req.Username,
+		Role:       simplebanksql.AccountMemberRole(req.Role),
+		SpendLimit: req.SpendLimit,
+		InvitedBy:  payload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidAccountMember) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, member)
+}
+
+// listAccountMembers lists the members of an account of the user, pending invitations included.
+func (s *Server) listAccountMembers(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionView, 0); !valid {
+		return
+	}
+
+	members, err := s.store.ListAccountMembers(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// acceptInvitation accepts the invitation of the user to the account, the user gets the access of its role.
+func (s *Server) acceptInvitation(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	member, err := s.store.AcceptAccountMember(ctx, simplebanksql.AcceptAccountMemberParams{
+		AccountID: uri.ID,
+		Username:  payload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := errors.New("no pending invitation to the account")
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+type accountMemberURI struct {
+	ID       int64  `uri:"id" binding:"required,min=1"`
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// removeAccountMember removes a member from an account the user manages. Any member can leave the account
+// or decline its invitation by removing itself, but the holder of the account can't be removed.
+func (s *Server) removeAccountMember(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	var uri accountMemberURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if uri.Username != payload.Username {
+		if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionManage, 0); !valid {
+			return
+		}
+	}
+
+	member, err := s.store.RemoveAccountMemberTx(ctx, uri.ID, uri.Username)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows), errors.Is(err, store.ErrNotAccountMember):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, store.ErrInvalidAccountMember):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+// listInvitations lists the pending invitations of the user to accounts of other users.
+func (s *Server) listInvitations(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	invitations, err := s.store.ListPendingInvitations(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
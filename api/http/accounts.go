@@ -4,11 +4,14 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/access"
 	"github.com/orlandorode97/simple-bank/pkg/token"
+	"github.com/orlandorode97/simple-bank/store"
 )
 
 func (s *Server) addAccountRoutes(r *gin.RouterGroup) {
@@ -17,11 +20,30 @@ func (s *Server) addAccountRoutes(r *gin.RouterGroup) {
 	accounts.POST("/", s.createAccount)
 	accounts.GET("/", s.listAccounts)
 	accounts.GET("/:id", s.getAccount)
+	accounts.DELETE("/:id", s.closeAccount)
+	accounts.GET("/:id/entries", s.listAccountEntries)
+	accounts.GET("/:id/statement", s.exportStatement)
+	accounts.PATCH("/:id/name", s.renameAccount)
+	accounts.POST("/:id/moves", s.movePocketFunds)
+	accounts.PATCH("/:id/status", s.changeAccountStatus)
+	accounts.GET("/:id/status_changes", s.listAccountStatusChanges)
+	accounts.GET("/:id/limits", s.getAccountLimits)
+	accounts.GET("/:id/members", s.listAccountMembers)
+	accounts.POST("/:id/members", s.inviteAccountMember)
+	accounts.POST("/:id/members/accept", s.acceptInvitation)
+	accounts.DELETE("/:id/members/:username", s.removeAccountMember)
 }
 
+// defaultAccountName names the account when the user does not give it a name.
+const defaultAccountName = "Main"
+
 type createAccountRequest struct {
 	Owner      string `json:"owner" binding:"required"`
-	CurrencyID int64  `json:"currency_id" binding:"required,oneof=1 2 3 4 5"`
+	CurrencyID int64  `json:"currency_id" binding:"required_without=Currency,min=0"`
+	// Currency is the code of the currency such as USD, it can be sent instead of the currency id.
+	Currency string `json:"currency" binding:"required_without=CurrencyID"`
+	// Name tells apart the pockets of the user in the same currency, such as Savings or Taxes.
+	Name string `json:"name" binding:"omitempty,max=64"`
 }
 
 func (s *Server) createAccount(ctx *gin.Context) {
@@ -33,15 +55,25 @@ func (s *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
-	arg := simplebanksql.CreateAccountParams{
+	currency, valid := s.enabledCurrency(ctx, req.CurrencyID, req.Currency)
+	if !valid {
+		return
+	}
+
+	arg := store.CreateAccountTxParams{
 		Owner:      payload.Username,
-		CurrencyID: req.CurrencyID,
-		Balance:    0,
+		CurrencyID: currency.ID,
+		Name:       req.Name,
+	}
+
+	if arg.Name == "" {
+		arg.Name = defaultAccountName
 	}
 
-	account, err := s.store.CreateAccount(ctx, arg)
+	account, err := s.store.CreateAccountTx(ctx, arg)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
 			switch pqErr.Code.Name() {
 			case "foreign_key_violation":
 				ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
@@ -54,6 +86,8 @@ func (s *Server) createAccount(ctx *gin.Context) {
 				return
 			}
 		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
 	ctx.JSON(http.StatusCreated, account)
@@ -65,40 +99,369 @@ type getAccountRequest struct {
 
 // getAccount gets the account that the user owns
 func (s *Server) getAccount(ctx *gin.Context) {
-
-	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
 	var req getAccountRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	account, err := s.store.GetAccount(ctx, req.ID)
-	if errors.Is(err, sql.ErrNoRows) {
-		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	account, valid := s.authorizedAccount(ctx, req.ID, access.ActionView, 0)
+	if !valid {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, account)
+}
+
+type listAccountEntriesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+	// FromDate and ToDate filter the entries created between both dates, both inclusive.
+	FromDate  *time.Time `form:"from_date" time_format:"2006-01-02"`
+	ToDate    *time.Time `form:"to_date" time_format:"2006-01-02"`
+	Direction string     `form:"direction" binding:"omitempty,oneof=credit debit"`
+}
+
+// listAccountEntries lists the entries of an account that the user owns, newest first,
+// with the running balance after each entry and the counterparty account of its transfer.
+func (s *Server) listAccountEntries(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listAccountEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.FromDate != nil && req.ToDate != nil && req.ToDate.Before(*req.FromDate) {
+		err := errors.New("to_date is before from_date")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
+	if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionView, 0); !valid {
+		return
+	}
+
+	arg := simplebanksql.ListAccountEntriesParams{
+		AccountID:  uri.ID,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	}
+
+	if req.FromDate != nil {
+		arg.FromDate = sql.NullTime{Time: *req.FromDate, Valid: true}
+	}
+
+	if req.ToDate != nil { // the whole to date is included.
+		arg.ToDate = sql.NullTime{Time: req.ToDate.AddDate(0, 0, 1), Valid: true}
+	}
+
+	if req.Direction != "" {
+		arg.Direction = sql.NullString{String: req.Direction, Valid: true}
+	}
+
+	entries, err := s.store.ListAccountEntries(ctx, arg)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	if account.Owner != payload.Username { // Check if the user owns an account based on the auth token payload username
-		err := errors.New("account does not belong to authenticated user")
-		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	ctx.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+type closeAccountRequest struct {
+	// SweepAccountID receives the remaining balance, it is only required when the balance is not zero.
+	SweepAccountID int64  `form:"sweep_account_id" binding:"omitempty,min=1"`
+	Reason         string `form:"reason" binding:"max=255"`
+}
+
+// closeAccount moves the remaining balance to another account of the user and closes the account.
+// The account and its history are kept.
+func (s *Server) closeAccount(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req closeAccountRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionManage, 0); !valid {
+		return
+	}
+
+	if req.Reason == "" {
+		req.Reason = "closed by the owner"
+	}
+
+	result, err := s.store.CloseAccountTx(ctx, store.CloseAccountTxParams{
+		AccountID:      uri.ID,
+		SweepAccountID: req.SweepAccountID,
+		ClosedBy:       payload.Username,
+		Reason:         req.Reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, store.ErrInvalidSweepAccount):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, store.ErrInvalidStatusTransition),
+			errors.Is(err, store.ErrAccountNotEmpty),
+			errors.Is(err, store.ErrAccountNotActive),
+			errors.Is(err, store.ErrExchangeRateNotFound):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type renameAccountRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// renameAccount renames an account that the user owns, the name must be unique among the pockets of the same currency.
+func (s *Server) renameAccount(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req renameAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionManage, 0); !valid {
+		return
+	}
+
+	account, err := s.store.UpdateAccountName(ctx, simplebanksql.UpdateAccountNameParams{
+		ID:   uri.ID,
+		Name: req.Name,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, account)
 }
 
+type movePocketFundsRequest struct {
+	ToAccountID int64 `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64 `json:"amount" binding:"required,gt=0"`
+}
+
+// movePocketFunds instantly moves funds to another pocket of the user in the same currency.
+func (s *Server) movePocketFunds(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req movePocketFundsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// The to account is verified by the move itself, it must belong to the same user.
+	if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionSpend, req.Amount); !valid {
+		return
+	}
+
+	result, err := s.store.MovePocketFundsTx(ctx, store.MovePocketFundsTxParams{
+		FromAccountID: uri.ID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, store.ErrInvalidPocketMove):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, store.ErrInsufficientFunds), errors.Is(err, store.ErrAccountNotActive):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// getAccountLimits reports the remaining daily and monthly spending limits of an account of the user,
+// and of the user itself in the currency of the account.
+func (s *Server) getAccountLimits(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionView, 0); !valid {
+		return
+	}
+
+	result, err := s.store.RemainingLimits(ctx, store.RemainingLimitsParams{
+		AccountID: uri.ID,
+		Username:  payload.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type changeAccountStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active frozen closed"`
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// changeAccountStatus freezes, closes, or reactivates a dormant account that the user owns.
+// Unfreezing an account is reserved to operators.
+func (s *Server) changeAccountStatus(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req changeAccountStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionManage, 0); !valid {
+		return
+	}
+
+	result, err := s.store.ChangeAccountStatusTx(ctx, store.ChangeAccountStatusTxParams{
+		AccountID: uri.ID,
+		Status:    simplebanksql.AccountStatus(req.Status),
+		ChangedBy: payload.Username,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrInvalidStatusTransition), errors.Is(err, store.ErrAccountNotEmpty):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type listAccountStatusChangesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listAccountStatusChanges lists the status changes of an account that the user owns, newest first.
+func (s *Server) listAccountStatusChanges(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listAccountStatusChangesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionView, 0); !valid {
+		return
+	}
+
+	changes, err := s.store.ListAccountStatusChanges(ctx, simplebanksql.ListAccountStatusChangesParams{
+		AccountID: uri.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status_changes": changes})
+}
+
+// authorizedAccount valids the account exists and the authenticated user is a member allowed to do the action,
+// amount is checked against the spend limit of the member when the action spends funds.
+func (s *Server) authorizedAccount(ctx *gin.Context, accountID int64, action access.Action, amount int64) (*simplebanksql.Account, bool) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	account, err := s.store.AuthorizeAccount(ctx, store.AuthorizeAccountParams{
+		AccountID: accountID,
+		Username:  payload.Username,
+		Action:    action,
+		Amount:    amount,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows), errors.Is(err, store.ErrNotAccountMember):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, access.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return &account, false
+	}
+
+	return &account, true
+}
+
 type listAccountsRequest struct {
 	PageID   int32 `form:"page_id" binding:"required,min=1"`
 	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
 }
 
-// listAccounts lists all accounts that a user owns
+// currencyAccounts groups the accounts of a user in the same currency.
+type currencyAccounts struct {
+	CurrencyID int64                   `json:"currency_id"`
+	Accounts   []simplebanksql.Account `json:"accounts"`
+}
+
+// listAccounts lists all accounts that a user is a member of grouped by currency
 func (s *Server) listAccounts(ctx *gin.Context) {
 	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
 
@@ -108,10 +471,11 @@ func (s *Server) listAccounts(ctx *gin.Context) {
 		return
 	}
 
+	// Joint accounts the user is a member of are listed along with the accounts the user holds.
 	arg := simplebanksql.ListAccountsParams{
-		Owner:  payload.Username,
-		Limit:  req.PageSize,                    // limit is the page size
-		Offset: (req.PageID - 1) * req.PageSize, // records to skip
+		Username:   payload.Username,
+		PageLimit:  req.PageSize,                    // limit is the page size
+		PageOffset: (req.PageID - 1) * req.PageSize, // records to skip
 	}
 
 	accounts, err := s.store.ListAccounts(ctx, arg)
@@ -120,5 +484,19 @@ func (s *Server) listAccounts(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"accounts": accounts})
+	ctx.JSON(http.StatusOK, gin.H{"currencies": groupByCurrency(accounts)})
+}
+
+// groupByCurrency groups the accounts by currency, the accounts must be sorted by currency.
+func groupByCurrency(accounts []simplebanksql.Account) []currencyAccounts {
+	groups := make([]currencyAccounts, 0)
+	for _, account := range accounts {
+		if len(groups) == 0 || groups[len(groups)-1].CurrencyID != account.CurrencyID {
+			groups = append(groups, currencyAccounts{CurrencyID: account.CurrencyID})
+		}
+		last := &groups[len(groups)-1]
+		last.Accounts = append(last.Accounts, account)
+	}
+
+	return groups
 }
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/policy"
+	"github.com/orlandorode97/simple-bank/pkg/token"
+	"github.com/orlandorode97/simple-bank/store"
+)
+
+// addAdminRoutes adds the routes of the staff of the bank, every route requires a permission of the role of the user.
+func (s *Server) addAdminRoutes(r *gin.RouterGroup) {
+	admin := r.Group("/admin")
+
+	admin.GET("/users", permissionMiddleware(policy.PermissionSearchUsers), s.searchUsers)
+	admin.GET("/users/:username/accounts", permissionMiddleware(policy.PermissionViewAccounts), s.listUserAccounts)
+	admin.PUT("/users/:username/role", permissionMiddleware(policy.PermissionManageRoles), s.updateUserRole)
+	admin.DELETE("/users/:username/sessions", permissionMiddleware(policy.PermissionBlockSessions), s.blockUserSessions)
+	admin.GET("/accounts/:id", permissionMiddleware(policy.PermissionViewAccounts), s.adminGetAccount)
+	admin.POST("/accounts/:id/freeze", permissionMiddleware(policy.PermissionFreezeAccounts), s.freezeAccount)
+	admin.POST("/accounts/:id/unfreeze", permissionMiddleware(policy.PermissionFreezeAccounts), s.unfreezeAccount)
+	admin.PUT("/accounts/:id/overdraft_limit", permissionMiddleware(policy.PermissionManageOverdrafts), s.updateOverdraftLimit)
+	admin.PUT("/accounts/:id/interest_plan", permissionMiddleware(policy.PermissionManageInterestPlans), s.updateAccountInterestPlan)
+	admin.POST("/accounts/:id/deposits", permissionMiddleware(policy.PermissionPostSettlements), s.createDeposit)
+	admin.POST("/accounts/:id/withdrawals", permissionMiddleware(policy.PermissionPostSettlements), s.createWithdrawal)
+}
+
+type searchUsersRequest struct {
+	// Query is matched against the username, the full name and the email.
+	Query    string `form:"q" binding:"required,min=2"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// searchUsers looks up users whose username, full name or email contains the query, ordered by username.
+func (s *Server) searchUsers(ctx *gin.Context) {
+	var req searchUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	users, err := s.store.SearchUsers(ctx, simplebanksql.SearchUsersParams{
+		Query:      req.Query,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resp := make([]userResponse, 0, len(users))
+	for _, user := range users {
+		resp = append(resp, newUserResponse(user))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"users": resp})
+}
+
+type adminUserURI struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// listUserAccounts lists the accounts any user is a member of grouped by currency.
+func (s *Server) listUserAccounts(ctx *gin.Context) {
+	var uri adminUserURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listAccountsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	accounts, err := s.store.ListAccounts(ctx, simplebanksql.ListAccountsParams{
+		Username:   uri.Username,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"currencies": groupByCurrency(accounts)})
+}
+
+type updateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=customer teller admin"`
+}
+
+// updateUserRole changes the role of a user, it applies to the tokens issued from the next login or refresh.
+// Admins can't change their own role so there is always an admin left.
+func (s *Server) updateUserRole(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	var uri adminUserURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if uri.Username == payload.Username {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(errors.New("unable to change your own role")))
+		return
+	}
+
+	user, err := s.store.UpdateUserRole(ctx, simplebanksql.UpdateUserRoleParams{
+		Username: uri.Username,
+		Role:     simplebanksql.UserRole(req.Role),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// blockUserSessions logs out any user everywhere, access tokens already issued keep working until they expire.
+func (s *Server) blockUserSessions(ctx *gin.Context) {
+	var uri adminUserURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := s.store.GetUser(ctx, uri.Username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	revoked, err := s.store.BlockUserSessions(ctx, uri.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// adminGetAccount returns any account, whether the user is a member of it or not.
+func (s *Server) adminGetAccount(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := s.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, account)
+}
+
+type freezeAccountRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// freezeAccount freezes any account, it can't send or receive transfers until it is unfrozen.
+func (s *Server) freezeAccount(ctx *gin.Context) {
+	s.adminChangeAccountStatus(ctx, simplebanksql.AccountStatusFrozen)
+}
+
+// unfreezeAccount reactivates a frozen account.
+func (s *Server) unfreezeAccount(ctx *gin.Context) {
+	s.adminChangeAccountStatus(ctx, simplebanksql.AccountStatusActive)
+}
+
+// adminChangeAccountStatus moves any account to the status as an operator, the change is recorded
+// with the username of the staff member.
+func (s *Server) adminChangeAccountStatus(ctx *gin.Context, status simplebanksql.AccountStatus) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req freezeAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := s.store.ChangeAccountStatusTx(ctx, store.ChangeAccountStatusTxParams{
+		AccountID: uri.ID,
+		Status:    status,
+		ChangedBy: payload.Username,
+		Reason:    req.Reason,
+		Operator:  true,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, store.ErrInvalidStatusTransition):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type updateOverdraftLimitRequest struct {
+	// OverdraftLimit is how far below zero the balance can go in minor units, zero removes the overdraft.
+	OverdraftLimit *int64 `json:"overdraft_limit" binding:"required,min=0"`
+}
+
+// updateOverdraftLimit sets the overdraft limit of any account.
+func (s *Server) updateOverdraftLimit(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateOverdraftLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := s.store.UpdateAccountOverdraftLimit(ctx, simplebanksql.UpdateAccountOverdraftLimitParams{
+		ID:             uri.ID,
+		OverdraftLimit: *req.OverdraftLimit,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, account)
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/policy"
+	"github.com/orlandorode97/simple-bank/store"
+)
+
+func (s *Server) addCurrencyRoutes(r *gin.RouterGroup) {
+	currencies := r.Group("/currencies")
+
+	currencies.GET("/", s.listCurrencies)
+
+	admin := currencies.Group("/", permissionMiddleware(policy.PermissionManageCurrencies))
+	admin.POST("/", s.createCurrency)
+	admin.PATCH("/:id", s.updateCurrency)
+}
+
+// listCurrencies lists the currency catalog, disabled currencies included.
+func (s *Server) listCurrencies(ctx *gin.Context) {
+	currencies, err := s.store.ListCurrencies(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"currencies": currencies})
+}
+
+type createCurrencyRequest struct {
+	// Code is the ISO 4217 alphabetic code such as USD.
+	Code     string `json:"code" binding:"required,len=3,alpha"`
+	Exponent *int16 `json:"exponent" binding:"required,min=0,max=4"`
+	Symbol   string `json:"symbol" binding:"max=8"`
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled"`
+}
+
+// createCurrency adds a currency to the catalog along with its system accounts.
+func (s *Server) createCurrency(ctx *gin.Context) {
+	var req createCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := simplebanksql.CreateCurrencyParams{
+		Code:     req.Code,
+		Exponent: *req.Exponent,
+		Symbol:   req.Symbol,
+		Enabled:  true,
+	}
+
+	if req.Enabled != nil {
+		arg.Enabled = *req.Enabled
+	}
+
+	result, err := s.store.CreateCurrencyTx(ctx, arg)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+type currencyURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type updateCurrencyRequest struct {
+	Symbol *string `json:"symbol" binding:"omitempty,max=8"`
+	// Enabled disables a currency so no new accounts and transfers are made in it, existing balances are kept.
+	Enabled *bool `json:"enabled"`
+}
+
+// updateCurrency changes the symbol of a currency of the catalog or enables and disables it.
+func (s *Server) updateCurrency(ctx *gin.Context) {
+	var uri currencyURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := simplebanksql.UpdateCurrencyParams{ID: uri.ID}
+	if req.Symbol != nil {
+		arg.Symbol = sql.NullString{String: *req.Symbol, Valid: true}
+	}
+
+	if req.Enabled != nil {
+		arg.Enabled = sql.NullBool{Bool: *req.Enabled, Valid: true}
+	}
+
+	currency, err := s.store.UpdateCurrency(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, currency)
+}
+
+// enabledCurrency valids the currency is an enabled currency of the catalog, the code is used instead of the id
+// when it is set.
+func (s *Server) enabledCurrency(ctx *gin.Context, currencyID int64, code string) (*simplebanksql.Currency, bool) {
+	currency, err := s.store.EnabledCurrency(ctx, store.EnabledCurrencyParams{ID: currencyID, Code: code})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrCurrencyNotFound), errors.Is(err, store.ErrCurrencyDisabled):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return &currency, false
+	}
+
+	return &currency, true
+}
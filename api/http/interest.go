@@ -0,0 +1,89 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+)
+
+func (s *Server) addInterestPlanRoutes(r *gin.RouterGroup) {
+	interestPlans := r.Group("/interest_plans")
+
+	interestPlans.GET("/", s.listInterestPlans)
+}
+
+type interestPlanResponse struct {
+	simplebanksql.InterestPlan
+	Tiers []simplebanksql.InterestPlanTier `json:"tiers"`
+}
+
+// listInterestPlans lists the interest plans that can be attached to accounts with their tiers.
+func (s *Server) listInterestPlans(ctx *gin.Context) {
+	plans, err := s.store.ListInterestPlans(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resp := make([]interestPlanResponse, 0, len(plans))
+	for _, plan := range plans {
+		tiers, err := s.store.ListInterestPlanTiers(ctx, plan.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		resp = append(resp, interestPlanResponse{InterestPlan: plan, Tiers: tiers})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"interest_plans": resp})
+}
+
+type updateAccountInterestPlanRequest struct {
+	// PlanID attaches the plan to the account, the account stops earning interest when it is not provided.
+	PlanID int64 `json:"plan_id" binding:"min=0"`
+}
+
+// updateAccountInterestPlan attaches an interest plan to any account, or detaches it. Plans are attached by the
+// staff to the savings products of the bank. Interest already accrued is capitalized at the end of the month either way.
+func (s *Server) updateAccountInterestPlan(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateAccountInterestPlanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := simplebanksql.UpdateAccountInterestPlanParams{ID: uri.ID}
+	if req.PlanID != 0 {
+		if _, err := s.store.GetInterestPlan(ctx, req.PlanID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.InterestPlanID = sql.NullInt64{Int64: req.PlanID, Valid: true}
+	}
+
+	account, err := s.store.UpdateAccountInterestPlan(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, account)
+}
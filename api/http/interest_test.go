@@ -0,0 +1,74 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/orlandorode97/simple-bank/config"
+	"github.com/orlandorode97/simple-bank/store/mockdb"
+)
+
+func TestUpdateAccountInterestPlanRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tcs := []struct {
+		desc string
+		role string
+		path string
+
+		wantStatus int
+	}{
+		{
+			desc:       "failure - customer attaches an interest plan",
+			role:       "customer",
+			path:       "/api/v1/admin/accounts/1/interest_plan",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			desc:       "failure - teller attaches an interest plan",
+			role:       "teller",
+			path:       "/api/v1/admin/accounts/1/interest_plan",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			desc:       "failure - customer route was removed",
+			role:       "customer",
+			path:       "/api/v1/accounts/1/interest_plan",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server, err := NewServer(config.Config{
+				SymmetricKey: "iQ9m6CjMXwEFEdTDYLrLw3krZq6ewKep",
+			}, mockdb.NewMockStore(ctrl), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			accessToken, _, err := server.tokenMaker.CreateToken("orlandorode97", tc.role, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPut, tc.path, bytes.NewBufferString(`{"plan_id": 1}`))
+			req.Header.Set(authorizationHeaderKey, "Bearer "+accessToken)
+
+			recorder := httptest.NewRecorder()
+			server.handler.ServeHTTP(recorder, req)
+
+			if recorder.Code != tc.wantStatus {
+				t.Errorf("response status: got %d want %d: %s", recorder.Code, tc.wantStatus, recorder.Body)
+			}
+		})
+	}
+}
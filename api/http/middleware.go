@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/orlandorode97/simple-bank/pkg/policy"
 	"github.com/orlandorode97/simple-bank/pkg/token"
 )
 
@@ -53,3 +54,17 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		ctx.Next()                                 // Continue to the next handler
 	}
 }
+
+// permissionMiddleware only lets the users whose role has the permission through, it must run after authMiddleware.
+func permissionMiddleware(permission policy.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+		if err := policy.Authorize(payload.Role, permission); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
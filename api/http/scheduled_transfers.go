@@ -0,0 +1,285 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/schedule"
+	"github.com/orlandorode97/simple-bank/pkg/token"
+)
+
+func (s *Server) addScheduledTransferRoutes(r *gin.RouterGroup) {
+	scheduledTransfers := r.Group("/scheduled_transfers")
+
+	scheduledTransfers.POST("/", s.createScheduledTransfer)
+	scheduledTransfers.GET("/", s.listScheduledTransfers)
+	scheduledTransfers.GET("/:id", s.getScheduledTransfer)
+	scheduledTransfers.PATCH("/:id", s.updateScheduledTransfer)
+	scheduledTransfers.DELETE("/:id", s.deleteScheduledTransfer)
+}
+
+type createScheduledTransferRequest struct {
+	FromAccountID int64 `json:"from_account_id" binding:"required_without=FromAccountNumber,min=0"`
+	ToAccountID   int64 `json:"to_account_id" binding:"required_without=ToAccountNumber,min=0"`
+	Amount        int64 `json:"amount" binding:"required,gt=1"`
+	CurrencyID    int64 `json:"currency_id" binding:"required_without=Currency,min=0"`
+	// Currency is the code of the currency such as USD, it can be sent instead of the currency id.
+	Currency string `json:"currency" binding:"required_without=CurrencyID"`
+	// FromAccountNumber and ToAccountNumber can be sent instead of the account ids.
+	FromAccountNumber string `json:"from_account_number" binding:"required_without=FromAccountID"`
+	ToAccountNumber   string `json:"to_account_number" binding:"required_without=ToAccountID"`
+	// Schedule is a cron expression such as "0 9 1 * *" or an interval rule such as "@every 168h".
+	Schedule string `json:"schedule" binding:"required"`
+	// StartAt delays the first run, the first run is the next one after now otherwise.
+	StartAt *time.Time `json:"start_at"`
+	EndAt   *time.Time `json:"end_at"`
+}
+
+// createScheduledTransfer creates a standing order from an account that the user owns.
+func (s *Server) createScheduledTransfer(ctx *gin.Context) {
+	var req createScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	startAt := time.Now()
+	if req.StartAt != nil && req.StartAt.After(startAt) {
+		startAt = *req.StartAt
+	}
+
+	nextRunAt, err := schedule.Next(req.Schedule, startAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.EndAt != nil && req.EndAt.Before(nextRunAt) {
+		err := errors.New("end_at is before the first run of the schedule")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !s.resolveAccountNumber(ctx, req.FromAccountNumber, &req.FromAccountID) ||
+		!s.resolveAccountNumber(ctx, req.ToAccountNumber, &req.ToAccountID) {
+		return
+	}
+
+	currency, valid := s.enabledCurrency(ctx, req.CurrencyID, req.Currency)
+	if !valid {
+		return
+	}
+
+	// The spend limit of a member applies to every run.
+	if _, valid := s.validAccount(ctx, req.FromAccountID, currency.ID, req.Amount); !valid {
+		return
+	}
+
+	if _, valid := s.findAccount(ctx, req.ToAccountID); !valid {
+		return
+	}
+
+	arg := simplebanksql.CreateScheduledTransferParams{
+		Owner:         payload.Username,
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Schedule:      req.Schedule,
+		NextRunAt:     nextRunAt,
+	}
+
+	if req.EndAt != nil {
+		arg.EndAt = sql.NullTime{Time: *req.EndAt, Valid: true}
+	}
+
+	scheduledTransfer, err := s.store.CreateScheduledTransfer(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, scheduledTransfer)
+}
+
+type listScheduledTransfersRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listScheduledTransfers lists the scheduled transfers of the user.
+func (s *Server) listScheduledTransfers(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	var req listScheduledTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduledTransfers, err := s.store.ListScheduledTransfers(ctx, simplebanksql.ListScheduledTransfersParams{
+		Owner:  payload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"scheduled_transfers": scheduledTransfers})
+}
+
+type scheduledTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getScheduledTransfer gets a scheduled transfer of the user.
+func (s *Server) getScheduledTransfer(ctx *gin.Context) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduledTransfer, valid := s.findScheduledTransfer(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduledTransfer)
+}
+
+type updateScheduledTransferRequest struct {
+	Amount   *int64     `json:"amount" binding:"omitempty,gt=1"`
+	Schedule *string    `json:"schedule"`
+	EndAt    *time.Time `json:"end_at"`
+	// Status pauses, resumes or cancels the scheduled transfer.
+	Status *simplebanksql.ScheduledTransferStatus `json:"status" binding:"omitempty,oneof=active paused cancelled"`
+}
+
+// updateScheduledTransfer updates a scheduled transfer of the user, the next run is calculated again
+// when the schedule changes or the scheduled transfer is resumed.
+func (s *Server) updateScheduledTransfer(ctx *gin.Context) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduledTransfer, valid := s.findScheduledTransfer(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	switch scheduledTransfer.Status {
+	case simplebanksql.ScheduledTransferStatusCompleted, simplebanksql.ScheduledTransferStatusCancelled:
+		err := errors.New("scheduled transfer is already " + string(scheduledTransfer.Status))
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return
+	}
+
+	arg := simplebanksql.UpdateScheduledTransferParams{
+		ID: uri.ID,
+	}
+
+	if req.Amount != nil {
+		arg.Amount = sql.NullInt64{Int64: *req.Amount, Valid: true}
+	}
+
+	if req.Status != nil {
+		arg.Status = simplebanksql.NullScheduledTransferStatus{ScheduledTransferStatus: *req.Status, Valid: true}
+	}
+
+	rule := scheduledTransfer.Schedule
+	if req.Schedule != nil {
+		rule = *req.Schedule
+		arg.Schedule = sql.NullString{String: rule, Valid: true}
+	}
+
+	resumed := req.Status != nil && *req.Status == simplebanksql.ScheduledTransferStatusActive &&
+		scheduledTransfer.Status == simplebanksql.ScheduledTransferStatusPaused
+
+	nextRunAt := scheduledTransfer.NextRunAt
+	if req.Schedule != nil || resumed {
+		var err error
+		nextRunAt, err = schedule.Next(rule, time.Now())
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.NextRunAt = sql.NullTime{Time: nextRunAt, Valid: true}
+	}
+
+	if req.EndAt != nil {
+		if req.EndAt.Before(nextRunAt) {
+			err := errors.New("end_at is before the next run of the schedule")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.EndAt = sql.NullTime{Time: *req.EndAt, Valid: true}
+	}
+
+	scheduledTransfer, err := s.store.UpdateScheduledTransfer(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduledTransfer)
+}
+
+// deleteScheduledTransfer deletes a scheduled transfer of the user, pending runs are skipped by the workers.
+func (s *Server) deleteScheduledTransfer(ctx *gin.Context) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.findScheduledTransfer(ctx, uri.ID); !valid {
+		return
+	}
+
+	if err := s.store.DeleteScheduledTransfer(ctx, uri.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// findScheduledTransfer valids the scheduled transfer exists and belongs to the authenticated user.
+func (s *Server) findScheduledTransfer(ctx *gin.Context, id int64) (simplebanksql.ScheduledTransfer, bool) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	scheduledTransfer, err := s.store.GetScheduledTransfer(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return scheduledTransfer, false
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return scheduledTransfer, false
+	}
+
+	if scheduledTransfer.Owner != payload.Username {
+		err := errors.New("scheduled transfer does not belong to authenticated user")
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return scheduledTransfer, false
+	}
+
+	return scheduledTransfer, true
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/orlandorode97/simple-bank/config"
 	"github.com/orlandorode97/simple-bank/pkg/token"
 	"github.com/orlandorode97/simple-bank/store"
+	"github.com/orlandorode97/simple-bank/workers"
 )
 
 // Server serves http requests, routes, config, token generation, and gRPC calls.
@@ -13,21 +14,33 @@ type Server struct {
 	handler    *gin.Engine
 	config     config.Config
 	tokenMaker token.Maker
+	// taskDistributor enqueues the work that is too large for a request.
+	taskDistributor workers.TaskDistributor
 }
 
-func NewServer(conf config.Config, store store.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(conf.SymmetricKey)
+func NewServer(conf config.Config, store store.Store, taskDistributor workers.TaskDistributor) (*Server, error) {
+	tokenMaker, err := token.NewMaker(token.Config{
+		Kind:         conf.TokenMaker,
+		SymmetricKey: conf.SymmetricKey,
+		PrivateKey:   conf.TokenPrivateKey,
+		KeyringFile:  conf.TokenKeyringFile,
+		Keyring:      conf.TokenKeyring,
+		GracePeriod:  conf.TokenKeyGracePeriod,
+	})
 	if err != nil {
 		return nil, err
 	}
 	server := &Server{
-		store:      store,
-		config:     conf,
-		tokenMaker: tokenMaker,
+		store:           store,
+		config:          conf,
+		tokenMaker:      tokenMaker,
+		taskDistributor: taskDistributor,
 	}
 
 	router := gin.New()
 
+	router.GET("/.well-known/jwks.json", server.jwks)
+
 	v1 := router.Group("/api/v1")
 
 	v1.POST("/login", server.login)
@@ -39,6 +52,12 @@ func NewServer(conf config.Config, store store.Store) (*Server, error) {
 
 	server.addAccountRoutes(v1)
 	server.addTransferRoutes(v1)
+	server.addScheduledTransferRoutes(v1)
+	server.addInterestPlanRoutes(v1)
+	server.addInvitationRoutes(v1)
+	server.addCurrencyRoutes(v1)
+	server.addSessionRoutes(v1)
+	server.addAdminRoutes(v1)
 
 	server.handler = router
 
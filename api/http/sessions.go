@@ -0,0 +1,103 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/token"
+)
+
+func (s *Server) addSessionRoutes(r *gin.RouterGroup) {
+	sessions := r.Group("/sessions")
+
+	sessions.GET("/", s.listSessions)
+	sessions.DELETE("/", s.revokeAllSessions)
+	sessions.DELETE("/:id", s.revokeSession)
+}
+
+// sessionResponse leaves out the refresh token of the session.
+type sessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	ClientIP   string    `json:"client_ip"`
+	IsBlocked  bool      `json:"is_blocked"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreateadAt time.Time `json:"createad_at"`
+}
+
+func newSessionResponse(session simplebanksql.Session) sessionResponse {
+	return sessionResponse{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		ClientIP:   session.ClientIp,
+		IsBlocked:  session.IsBlocked,
+		ExpiresAt:  session.ExpiresAt,
+		CreateadAt: session.CreateadAt,
+	}
+}
+
+// listSessions lists the sessions of the user that are neither revoked nor expired, newest first.
+func (s *Server) listSessions(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	sessions, err := s.store.ListActiveSessions(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resp := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, newSessionResponse(session))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"sessions": resp})
+}
+
+type sessionURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// revokeSession logs out a session of the user, its refresh token can't be used anymore.
+func (s *Server) revokeSession(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	var uri sessionURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	session, err := s.store.BlockSession(ctx, simplebanksql.BlockSessionParams{
+		ID:       uuid.MustParse(uri.ID),
+		Username: payload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSessionResponse(session))
+}
+
+// revokeAllSessions logs out the user everywhere, access tokens already issued keep working until they expire.
+func (s *Server) revokeAllSessions(ctx *gin.Context) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	revoked, err := s.store.BlockUserSessions(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
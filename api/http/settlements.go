@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orlandorode97/simple-bank/store"
+)
+
+type settlementRequest struct {
+	Amount int64 `json:"amount" binding:"required,gt=0"`
+	// ExternalReference identifies the operation in the external payment system, it can be used only once.
+	ExternalReference string `json:"external_reference" binding:"required,max=64"`
+}
+
+// createDeposit credits any account with funds coming from outside the bank, it is posted by the staff
+// once the funds arrived.
+func (s *Server) createDeposit(ctx *gin.Context) {
+	s.settle(ctx, s.store.DepositTx)
+}
+
+// createWithdrawal debits any account with funds leaving the bank, it is posted by the staff.
+func (s *Server) createWithdrawal(ctx *gin.Context) {
+	s.settle(ctx, s.store.WithdrawalTx)
+}
+
+// settle posts a deposit or a withdrawal against the system accounts of the account's currency.
+// Settlements are operations of the bank, they don't count towards the spending limits of the members.
+func (s *Server) settle(ctx *gin.Context, settleTx func(context.Context, store.SettlementTxParams) (store.TransferTxResult, error)) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req settlementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := settleTx(ctx, store.SettlementTxParams{
+		AccountID:         uri.ID,
+		Amount:            req.Amount,
+		ExternalReference: req.ExternalReference,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, store.ErrDuplicateReference):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		case errors.Is(err, store.ErrInsufficientFunds), errors.Is(err, store.ErrAccountNotActive):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
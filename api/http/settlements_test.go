@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/orlandorode97/simple-bank/config"
+	"github.com/orlandorode97/simple-bank/store"
+	"github.com/orlandorode97/simple-bank/store/mockdb"
+)
+
+func TestSettlementRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tcs := []struct {
+		desc       string
+		role       string
+		path       string
+		buildStubs func(store *mockdb.MockStore)
+
+		wantStatus int
+	}{
+		{
+			desc:       "failure - customer posts a deposit",
+			role:       "customer",
+			path:       "/api/v1/admin/accounts/1/deposits",
+			buildStubs: func(store *mockdb.MockStore) {},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			desc:       "failure - customer posts a withdrawal",
+			role:       "customer",
+			path:       "/api/v1/admin/accounts/1/withdrawals",
+			buildStubs: func(store *mockdb.MockStore) {},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			desc:       "failure - customer route was removed",
+			role:       "customer",
+			path:       "/api/v1/accounts/1/deposits",
+			buildStubs: func(store *mockdb.MockStore) {},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			desc: "success - teller posts a deposit",
+			role: "teller",
+			path: "/api/v1/admin/accounts/1/deposits",
+			buildStubs: func(mockStore *mockdb.MockStore) {
+				mockStore.EXPECT().DepositTx(gomock.Any(), store.SettlementTxParams{
+					AccountID:         1,
+					Amount:            100,
+					ExternalReference: "wire-1",
+				}).Return(store.TransferTxResult{}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(mockStore)
+
+			server, err := NewServer(config.Config{
+				SymmetricKey: "iQ9m6CjMXwEFEdTDYLrLw3krZq6ewKep",
+			}, mockStore, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			accessToken, _, err := server.tokenMaker.CreateToken("orlandorode97", tc.role, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body := bytes.NewBufferString(`{"amount": 100, "external_reference": "wire-1"}`)
+			req := httptest.NewRequest(http.MethodPost, tc.path, body)
+			req.Header.Set(authorizationHeaderKey, "Bearer "+accessToken)
+
+			recorder := httptest.NewRecorder()
+			server.handler.ServeHTTP(recorder, req)
+
+			if recorder.Code != tc.wantStatus {
+				t.Errorf("response status: got %d want %d: %s", recorder.Code, tc.wantStatus, recorder.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/access"
+	"github.com/orlandorode97/simple-bank/pkg/statement"
+	"github.com/orlandorode97/simple-bank/pkg/token"
+	"github.com/orlandorode97/simple-bank/store"
+	"github.com/orlandorode97/simple-bank/workers"
+)
+
+const (
+	// statementMaxDays and statementMaxEntries bound the statements rendered within the request,
+	// larger statements are emailed to the member who requested them.
+	statementMaxDays    = 93
+	statementMaxEntries = 1000
+
+	deliveryDownload = "download"
+	deliveryEmail    = "email"
+)
+
+type exportStatementRequest struct {
+	// FromDate and ToDate are both inclusive.
+	FromDate time.Time `form:"from_date" binding:"required" time_format:"2006-01-02"`
+	ToDate   time.Time `form:"to_date" binding:"required" time_format:"2006-01-02"`
+	Format   string    `form:"format" binding:"required,oneof=csv ofx camt053"`
+	// Delivery forces the statement to be emailed, by default it is downloaded when it is small enough.
+	Delivery string `form:"delivery" binding:"omitempty,oneof=download email"`
+}
+
+// exportStatement renders the statement of an account that the user owns as CSV, OFX or camt.053.
+func (s *Server) exportStatement(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req exportStatementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.ToDate.Before(req.FromDate) {
+		err := errors.New("to_date is before from_date")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	format, err := statement.ParseFormat(req.Format)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.authorizedAccount(ctx, uri.ID, access.ActionView, 0); !valid {
+		return
+	}
+
+	arg := store.StatementTxParams{
+		AccountID: uri.ID,
+		From:      req.FromDate,
+		To:        req.ToDate.AddDate(0, 0, 1), // the whole to date is included.
+	}
+
+	delivery, err := s.statementDelivery(ctx, req.Delivery, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if delivery == deliveryEmail {
+		payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+		err := s.taskDistributor.ExportStatement(ctx, &workers.PayloadExportStatement{
+			Username:  payload.Username,
+			AccountID: arg.AccountID,
+			From:      arg.From,
+			To:        arg.To,
+			Format:    format,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, gin.H{"message": "the statement will be emailed to your email address"})
+		return
+	}
+
+	stmt, err := s.store.StatementTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var body bytes.Buffer
+	if err := statement.Render(&body, format, stmt); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", format.Filename(stmt)))
+	ctx.Data(http.StatusOK, format.ContentType(), body.Bytes())
+}
+
+// statementDelivery decides whether the statement is downloaded or emailed.
+func (s *Server) statementDelivery(ctx *gin.Context, requested string, arg store.StatementTxParams) (string, error) {
+	if requested == deliveryEmail || arg.To.Sub(arg.From) > statementMaxDays*24*time.Hour {
+		return deliveryEmail, nil
+	}
+
+	entries, err := s.store.CountAccountEntries(ctx, simplebanksql.CountAccountEntriesParams{
+		AccountID: arg.AccountID,
+		FromDate:  arg.From,
+		ToDate:    arg.To,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if entries > statementMaxEntries {
+		return deliveryEmail, nil
+	}
+
+	return deliveryDownload, nil
+}
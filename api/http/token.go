@@ -7,12 +7,18 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/token"
+	"github.com/orlandorode97/simple-bank/store"
+	"github.com/orlandorode97/simple-bank/workers"
 )
 
 var (
 	ErrBlockedSession = errors.New("blocked session")
 	ErrInvalidSession = errors.New("invalid session user")
 	ErrExpiredSession = errors.New("expired session")
+	ErrNoPublicKeys   = errors.New("tokens are not signed with a public key")
 )
 
 type refreshAccessTokenRequest struct {
@@ -20,10 +26,15 @@ type refreshAccessTokenRequest struct {
 }
 
 type refreshAccessTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	SessionID             string    `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 }
 
+// refreshAccessToken rotates the session of the refresh token, a new refresh token is issued along with the access
+// token and the one presented can't be used again. Presenting it again revokes every session rotated from the same login.
 func (s *Server) refreshAccessToken(c *gin.Context) {
 	var req refreshAccessTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -62,16 +73,85 @@ func (s *Server) refreshAccessToken(c *gin.Context) {
 		return
 	}
 
-	token, accessPayload, err := s.tokenMaker.CreateToken(refreshPayload.Username, s.config.TokenDuration)
+	// The role is read again so role changes apply from the next refresh.
+	user, err := s.store.GetUser(c, session.Username)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	token, accessPayload, err := s.tokenMaker.CreateToken(user.Username, string(user.Role), s.config.TokenDuration)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The successor keeps the expiration of the session, rotating doesn't extend the login.
+	refreshToken, newRefreshPayload, err := s.tokenMaker.CreateToken(user.Username, string(user.Role), time.Until(session.ExpiresAt))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := s.store.RotateSessionTx(c, store.RotateSessionTxParams{
+		SessionID: session.ID,
+		Session: simplebanksql.CreateSessionParams{
+			ID:           newRefreshPayload.ID,
+			Username:     session.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    c.Request.UserAgent(),
+			ClientIp:     c.ClientIP(),
+			ExpiresAt:    newRefreshPayload.ExpiredAt,
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrRefreshTokenReused):
+			notifyErr := s.taskDistributor.NotifyRefreshTokenReused(c, &workers.PayloadNotifyRefreshTokenReused{
+				Username:   session.Username,
+				UserAgent:  c.Request.UserAgent(),
+				ClientIP:   c.ClientIP(),
+				Revoked:    result.Revoked,
+				DetectedAt: time.Now(),
+			}, asynq.Queue(workers.QueueCritial))
+			if notifyErr != nil {
+				c.JSON(http.StatusInternalServerError, errorResponse(notifyErr))
+				return
+			}
+			c.JSON(http.StatusUnauthorized, errorResponse(err))
+		case errors.Is(err, store.ErrSessionRevoked):
+			c.JSON(http.StatusUnauthorized, errorResponse(err))
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
 	resp := &refreshAccessTokenResponse{
-		AccessToken:          token,
-		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		SessionID:             result.Session.ID.String(),
+		AccessToken:           token,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: result.Session.ExpiresAt,
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// jwks publishes the public keys the tokens are signed with, so other services can verify them.
+// Makers and keyrings with symmetric keys have nothing to publish.
+func (s *Server) jwks(c *gin.Context) {
+	keySet, ok := s.tokenMaker.(token.KeySet)
+	if !ok {
+		c.JSON(http.StatusNotFound, errorResponse(ErrNoPublicKeys))
+		return
+	}
+
+	jwks := keySet.JWKS()
+	if len(jwks.Keys) == 0 {
+		c.JSON(http.StatusNotFound, errorResponse(ErrNoPublicKeys))
+		return
+	}
+
+	c.JSON(http.StatusOK, jwks)
+}
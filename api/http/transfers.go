@@ -4,25 +4,43 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/access"
+	"github.com/orlandorode97/simple-bank/pkg/accountnumber"
 	"github.com/orlandorode97/simple-bank/pkg/token"
 	"github.com/orlandorode97/simple-bank/store"
 )
 
+// idempotencyKeyHeader lets clients retry a transfer without moving money twice.
+const idempotencyKeyHeader = "Idempotency-Key"
+
 func (s *Server) addTransferRoutes(r *gin.RouterGroup) {
 	transfers := r.Group("/transfers")
 
 	transfers.POST("/", s.createTransfer)
+	transfers.POST("/authorize", s.authorizeTransfer)
+	transfers.POST("/batch", s.batchTransfer)
+	transfers.POST("/:id/reverse", s.reverseTransfer)
+	transfers.POST("/:id/capture", s.captureTransfer)
+	transfers.POST("/:id/void", s.voidTransfer)
 }
 
 type createTransferRequest struct {
-	FromAccountID int64 `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64 `json:"to_account_id" binding:"required,min=1"`
+	FromAccountID int64 `json:"from_account_id" binding:"required_without=FromAccountNumber,min=0"`
+	ToAccountID   int64 `json:"to_account_id" binding:"required_without=ToAccountNumber,omitempty,min=0,nefield=FromAccountID"`
 	Amount        int64 `json:"amount" binding:"required,gt=1"`
-	CurrencyID    int64 `json:"currency_id" binding:"required,oneof=1 2 3 4 5"`
+	CurrencyID    int64 `json:"currency_id" binding:"required_without=Currency,min=0"`
+	// Currency is the code of the currency such as USD, it can be sent instead of the currency id.
+	Currency string `json:"currency" binding:"required_without=CurrencyID"`
+	// FromAccountNumber and ToAccountNumber can be sent instead of the account ids.
+	FromAccountNumber string `json:"from_account_number" binding:"required_without=FromAccountID"`
+	ToAccountNumber   string `json:"to_account_number" binding:"required_without=ToAccountID,omitempty,nefield=FromAccountNumber"`
 }
 
 func (s *Server) createTransfer(ctx *gin.Context) {
@@ -32,55 +50,390 @@ func (s *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
+	s.transfer(ctx, req, 0)
+}
+
+type authorizeTransferRequest struct {
+	createTransferRequest
+	// ExpiresIn is the number of seconds the funds are held, the configured hold duration is used when it is not provided.
+	ExpiresIn int64 `json:"expires_in" binding:"omitempty,min=60,max=2592000"`
+}
+
+// authorizeTransfer holds the amount on the from account, the transfer is settled once it is captured.
+func (s *Server) authorizeTransfer(ctx *gin.Context) {
+	var req authorizeTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	holdDuration := s.config.HoldDuration
+	if req.ExpiresIn != 0 {
+		holdDuration = time.Duration(req.ExpiresIn) * time.Second
+	}
+
+	s.transfer(ctx, req.createTransferRequest, holdDuration)
+}
+
+// transfer moves the amount between the accounts, or only holds it when holdDuration is set.
+func (s *Server) transfer(ctx *gin.Context, req createTransferRequest, holdDuration time.Duration) {
+	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
+
+	if !s.resolveAccountNumber(ctx, req.FromAccountNumber, &req.FromAccountID) ||
+		!s.resolveAccountNumber(ctx, req.ToAccountNumber, &req.ToAccountID) {
+		return
+	}
+
+	currency, valid := s.enabledCurrency(ctx, req.CurrencyID, req.Currency)
+	if !valid {
+		return
+	}
+
 	arg := store.TransferTxParams{
 		FromAccountID: req.FromAccountID,
 		ToAccountID:   req.ToAccountID,
 		Amount:        req.Amount,
+		HoldDuration:  holdDuration,
+		InitiatedBy:   payload.Username,
+	}
+
+	if key := ctx.GetHeader(idempotencyKeyHeader); key != "" {
+		arg.Idempotency = &store.IdempotencyParams{
+			Key:      key,
+			Username: payload.Username,
+			Duration: s.config.IdempotencyKeyDuration,
+		}
 	}
 
+	if _, valid := s.validAccount(ctx, req.FromAccountID, currency.ID, req.Amount); !valid {
+		return
+	}
+
+	// The to account may hold a different currency, the amount is converted with the exchange rate in effect.
+	if _, valid := s.findAccount(ctx, req.ToAccountID); !valid {
+		return
+	}
+
+	result, err := s.store.TransferTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrSameAccount):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, store.ErrIdempotencyKeyConflict):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		case errors.Is(err, store.ErrExchangeRateNotFound),
+			errors.Is(err, store.ErrInsufficientFunds),
+			errors.Is(err, store.ErrAccountNotActive),
+			errors.Is(err, store.ErrSpendingLimitExceeded):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+type batchTransferLeg struct {
+	ToAccountID     int64  `json:"to_account_id" binding:"required_without=ToAccountNumber,min=0"`
+	Amount          int64  `json:"amount" binding:"required,gt=1"`
+	ToAccountNumber string `json:"to_account_number" binding:"required_without=ToAccountID"`
+}
+
+type batchTransferRequest struct {
+	FromAccountID     int64              `json:"from_account_id" binding:"required_without=FromAccountNumber,min=0"`
+	CurrencyID        int64              `json:"currency_id" binding:"required_without=Currency,min=0"`
+	Legs              []batchTransferLeg `json:"legs" binding:"required,min=1,max=100,dive"`
+	FromAccountNumber string             `json:"from_account_number" binding:"required_without=FromAccountID"`
+	Currency          string             `json:"currency" binding:"required_without=CurrencyID"`
+}
+
+// batchTransfer performs every leg from the same account within a single transaction, either all legs
+// are posted or none of them.
+func (s *Server) batchTransfer(ctx *gin.Context) {
 	payload := ctx.MustGet(string(authorizationKey)).(*token.Payload)
 
-	fromAccount, valid := s.validAccount(ctx, req.FromAccountID, req.CurrencyID)
-	if !valid {
+	var req batchTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	if fromAccount.Owner != payload.Username {
-		err := errors.New("form account does not belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if !s.resolveAccountNumber(ctx, req.FromAccountNumber, &req.FromAccountID) {
 		return
 	}
-	_, valid = s.validAccount(ctx, req.ToAccountID, req.CurrencyID)
+
+	currency, valid := s.enabledCurrency(ctx, req.CurrencyID, req.Currency)
 	if !valid {
 		return
 	}
 
-	transfer, err := s.store.TransferTx(ctx, arg)
+	for i := range req.Legs {
+		if !s.resolveAccountNumber(ctx, req.Legs[i].ToAccountNumber, &req.Legs[i].ToAccountID) {
+			return
+		}
+	}
+
+	// The spend limit of a member applies to the whole batch.
+	var total int64
+	for _, leg := range req.Legs {
+		if total > math.MaxInt64-leg.Amount {
+			total = math.MaxInt64
+			break
+		}
+		total += leg.Amount
+	}
+
+	if _, valid := s.validAccount(ctx, req.FromAccountID, currency.ID, total); !valid {
+		return
+	}
+
+	arg := store.BatchTransferTxParams{
+		FromAccountID: req.FromAccountID,
+		Legs:          make([]store.BatchTransferLeg, 0, len(req.Legs)),
+		InitiatedBy:   payload.Username,
+	}
+
+	for _, leg := range req.Legs {
+		arg.Legs = append(arg.Legs, store.BatchTransferLeg{
+			ToAccountID: leg.ToAccountID,
+			Amount:      leg.Amount,
+		})
+	}
+
+	result, err := s.store.BatchTransferTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, store.ErrInvalidBatchLeg):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, store.ErrExchangeRateNotFound),
+			errors.Is(err, store.ErrInsufficientFunds),
+			errors.Is(err, store.ErrAccountNotActive),
+			errors.Is(err, store.ErrSpendingLimitExceeded):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+type transferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type reverseTransferRequest struct {
+	// Amount to refund, when it is not provided the remaining amount of the transfer is refunded.
+	Amount int64 `json:"amount" binding:"min=0"`
+}
+
+// reverseTransfer refunds a transfer fully or partially, only a member of the account that received it allowed to spend can do it.
+func (s *Server) reverseTransfer(ctx *gin.Context) {
+	var uri transferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req reverseTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.receivedTransfer(ctx, uri.ID, access.ActionSpend, req.Amount); !valid {
+		return
+	}
+
+	result, err := s.store.ReverseTransferTx(ctx, store.ReverseTransferTxParams{
+		TransferID: uri.ID,
+		Amount:     req.Amount,
+	})
 	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrTransferNotReversible),
+			errors.Is(err, store.ErrRefundExceedsTransfer),
+			errors.Is(err, store.ErrInsufficientFunds),
+			errors.Is(err, store.ErrAccountNotActive):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+type captureTransferRequest struct {
+	// Amount to capture, when it is not provided the full held amount is captured.
+	Amount int64 `json:"amount" binding:"min=0"`
+}
+
+// captureTransfer settles an authorized transfer fully or partially, only a member of the account that receives it can do it.
+func (s *Server) captureTransfer(ctx *gin.Context) {
+	var uri transferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req captureTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.receivedTransfer(ctx, uri.ID, access.ActionReceive, 0); !valid {
+		return
+	}
+
+	result, err := s.store.CaptureTransferTx(ctx, store.CaptureTransferTxParams{
+		TransferID: uri.ID,
+		Amount:     req.Amount,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrTransferNotPending),
+			errors.Is(err, store.ErrHoldExpired),
+			errors.Is(err, store.ErrCaptureExceedsHold),
+			errors.Is(err, store.ErrInsufficientFunds),
+			errors.Is(err, store.ErrAccountNotActive):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// voidTransfer cancels an authorized transfer and releases its hold, only a member of the account that receives it can do it.
+func (s *Server) voidTransfer(ctx *gin.Context) {
+	var uri transferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := s.receivedTransfer(ctx, uri.ID, access.ActionReceive, 0); !valid {
+		return
+	}
+
+	result, err := s.store.VoidTransferTx(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, store.ErrTransferNotPending) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
-	ctx.JSON(http.StatusCreated, transfer)
+	ctx.JSON(http.StatusOK, result)
 }
 
-// validAccount  valids the account and the account's currency
-func (s *Server) validAccount(ctx *gin.Context, accountID int64, currencyID int64) (*simplebanksql.Account, bool) {
-	account, err := s.store.GetAccount(ctx, accountID)
+// receivedTransfer valids the transfer exists and was received by an account the authenticated user is allowed
+// to do the action with, an amount of zero stands for the whole transfer amount.
+func (s *Server) receivedTransfer(ctx *gin.Context, transferID int64, action access.Action, amount int64) (*simplebanksql.Transfer, bool) {
+	transfer, err := s.store.GetTransfer(ctx, transferID)
 	if errors.Is(err, sql.ErrNoRows) {
 		ctx.JSON(http.StatusNotFound, errorResponse(err))
-		return &account, false
+		return &transfer, false
 	}
 
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return &account, false
+		return &transfer, false
+	}
+
+	if amount == 0 {
+		amount = transfer.ToAmount
+	}
+
+	if _, valid := s.authorizedAccount(ctx, transfer.ToAccountID, action, amount); !valid {
+		return &transfer, false
+	}
+
+	return &transfer, true
+}
+
+// validAccount  valids the account, the account's currency, that the account is active to send funds,
+// and that the authenticated user is a member allowed to spend amount.
+func (s *Server) validAccount(ctx *gin.Context, accountID int64, currencyID int64, amount int64) (*simplebanksql.Account, bool) {
+	account, valid := s.authorizedAccount(ctx, accountID, access.ActionSpend, amount)
+	if !valid {
+		return account, false
+	}
+
+	if account.Status != simplebanksql.AccountStatusActive {
+		err := fmt.Errorf("%w: account [%d] is %s", store.ErrAccountNotActive, accountID, account.Status)
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return account, false
 	}
 
 	if account.CurrencyID != currencyID {
-		err = fmt.Errorf("account [%d] currency mismatch %v - %v", accountID, account.CurrencyID, currencyID)
+		err := fmt.Errorf("account [%d] currency mismatch %v - %v", accountID, account.CurrencyID, currencyID)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return account, false
+	}
+
+	return account, true
+}
+
+// findAccount valids the account exists.
+func (s *Server) findAccount(ctx *gin.Context, accountID int64) (*simplebanksql.Account, bool) {
+	account, err := s.store.GetAccount(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return &account, false
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return &account, false
 	}
 
 	return &account, true
 }
+
+// resolveAccountNumber sets accountID to the id of the account with the account number, nothing is done when the
+// number is empty. The check digits are validated before the account is looked up.
+func (s *Server) resolveAccountNumber(ctx *gin.Context, number string, accountID *int64) bool {
+	if number == "" {
+		return true
+	}
+
+	number = accountnumber.Normalize(number)
+	if err := accountnumber.Validate(number); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return false
+	}
+
+	account, err := s.store.GetAccountByNumber(ctx, number)
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("account number %s not found", number)))
+		return false
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if *accountID != 0 && *accountID != account.ID {
+		err := fmt.Errorf("account number %s does not belong to account [%d]", number, *accountID)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return false
+	}
+
+	*accountID = account.ID
+
+	return true
+}
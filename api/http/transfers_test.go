@@ -0,0 +1,61 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/orlandorode97/simple-bank/config"
+	"github.com/orlandorode97/simple-bank/store/mockdb"
+)
+
+func TestCreateTransferSameAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tcs := []struct {
+		desc string
+		body string
+	}{
+		{
+			desc: "failure - same account ids",
+			body: `{"from_account_id": 1, "to_account_id": 1, "amount": 100, "currency": "USD"}`,
+		},
+		{
+			desc: "failure - same account numbers",
+			body: `{"from_account_number": "SB0000000001", "to_account_number": "SB0000000001", "amount": 100, "currency": "USD"}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server, err := NewServer(config.Config{
+				SymmetricKey: "iQ9m6CjMXwEFEdTDYLrLw3krZq6ewKep",
+			}, mockdb.NewMockStore(ctrl), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			accessToken, _, err := server.tokenMaker.CreateToken("orlandorode97", "customer", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/transfers/", bytes.NewBufferString(tc.body))
+			req.Header.Set(authorizationHeaderKey, "Bearer "+accessToken)
+
+			recorder := httptest.NewRecorder()
+			server.handler.ServeHTTP(recorder, req)
+
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("response status: got %d want %d: %s", recorder.Code, http.StatusBadRequest, recorder.Body)
+			}
+		})
+	}
+}
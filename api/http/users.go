@@ -29,10 +29,22 @@ type userResponse struct {
 	Username          string    `json:"username"`
 	FullName          string    `json:"full_name"`
 	Email             string    `json:"email"`
+	Role              string    `json:"role"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreateadAt        time.Time `json:"createad_at"`
 }
 
+func newUserResponse(user simplebanksql.User) userResponse {
+	return userResponse{
+		Username:          user.Username,
+		FullName:          user.FullName,
+		Email:             user.Email,
+		Role:              string(user.Role),
+		PasswordChangedAt: user.PasswordChangedAt,
+		CreateadAt:        user.CreateadAt,
+	}
+}
+
 func (s *Server) createUser(ctx *gin.Context) {
 	var req createUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -63,15 +75,7 @@ func (s *Server) createUser(ctx *gin.Context) {
 		}
 	}
 
-	resp := userResponse{
-		Username:          user.Username,
-		FullName:          user.FullName,
-		Email:             user.Email,
-		PasswordChangedAt: user.PasswordChangedAt,
-		CreateadAt:        user.CreateadAt,
-	}
-
-	ctx.JSON(http.StatusCreated, resp)
+	ctx.JSON(http.StatusCreated, newUserResponse(user))
 }
 
 type loginUserRequest struct {
@@ -110,13 +114,13 @@ func (s *Server) login(c *gin.Context) {
 		return
 	}
 
-	token, accessPayload, err := s.tokenMaker.CreateToken(user.Username, s.config.TokenDuration)
+	token, accessPayload, err := s.tokenMaker.CreateToken(user.Username, string(user.Role), s.config.TokenDuration)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	refreshToken, refreshPayload, err := s.tokenMaker.CreateToken(user.Username, s.config.TokenRefreshDuration)
+	refreshToken, refreshPayload, err := s.tokenMaker.CreateToken(user.Username, string(user.Role), s.config.TokenRefreshDuration)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -130,6 +134,7 @@ func (s *Server) login(c *gin.Context) {
 		ClientIp:     c.ClientIP(),
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiredAt,
+		FamilyID:     refreshPayload.ID,
 	})
 
 	if err != nil {
@@ -143,13 +148,7 @@ func (s *Server) login(c *gin.Context) {
 		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
-		User: userResponse{
-			Username:          user.Username,
-			FullName:          user.FullName,
-			Email:             user.Email,
-			PasswordChangedAt: user.PasswordChangedAt,
-			CreateadAt:        user.CreateadAt,
-		},
+		User:                  newUserResponse(user),
 	}
 
 	c.JSON(http.StatusOK, resp)
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/store"
+)
+
+// accountStatusCommand lets operators change the status of any account:
+// simplebank account-status --account-id=1 --status=frozen --reason="..." --operator=name
+const accountStatusCommand = "account-status"
+
+// changeAccountStatus changes the status of the account and prints the result to stdout.
+func changeAccountStatus(s store.Store, accountID int64, status, reason, operator string) error {
+	if accountID <= 0 || status == "" || reason == "" || operator == "" {
+		return errors.New("--account-id, --status, --reason and --operator are required")
+	}
+
+	result, err := s.ChangeAccountStatusTx(context.Background(), store.ChangeAccountStatusTxParams{
+		AccountID: accountID,
+		Status:    simplebanksql.AccountStatus(status),
+		ChangedBy: operator,
+		Reason:    reason,
+		Operator:  true,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to change account status: %w", err)
+	}
+
+	return printJSON(result)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/money"
+	"github.com/orlandorode97/simple-bank/store"
+)
+
+const (
+	// createFeeScheduleCommand lets operators create fee schedules:
+	// simplebank create-fee-schedule --currency-id=1 [--tier=premium] [--flat=25] [--percentage=0.01] [--min-fee=50] [--max-fee=500]
+	createFeeScheduleCommand = "create-fee-schedule"
+	// accountTierCommand lets operators change the tier of an account: simplebank account-tier --account-id=1 --tier=premium
+	accountTierCommand = "account-tier"
+)
+
+// createFeeSchedule creates the fee schedule and prints it to stdout.
+func createFeeSchedule(s store.Store, currencyID int64, tier string, rule money.FeeRule) error {
+	if currencyID <= 0 {
+		return errors.New("--currency-id is required")
+	}
+
+	if rule.Flat < 0 || rule.Min < 0 || rule.Max < 0 || (rule.Max != 0 && rule.Max < rule.Min) {
+		return errors.New("--flat, --min-fee and --max-fee must be positive, and --max-fee can't be below --min-fee")
+	}
+
+	if rule.Percentage == "" {
+		rule.Percentage = "0"
+	}
+
+	// Validates the percentage before it is stored.
+	if _, err := rule.Apply(0); err != nil {
+		return fmt.Errorf("invalid --percentage: %w", err)
+	}
+
+	arg := simplebanksql.CreateFeeScheduleParams{
+		CurrencyID: currencyID,
+		FlatAmount: rule.Flat,
+		Percentage: rule.Percentage,
+		MinFee:     rule.Min,
+	}
+
+	if tier != "" {
+		arg.AccountTier = simplebanksql.NullAccountTier{AccountTier: simplebanksql.AccountTier(tier), Valid: true}
+	}
+
+	if rule.Max != 0 {
+		arg.MaxFee = sql.NullInt64{Int64: rule.Max, Valid: true}
+	}
+
+	schedule, err := s.CreateFeeSchedule(context.Background(), arg)
+	if err != nil {
+		return fmt.Errorf("unable to create fee schedule: %w", err)
+	}
+
+	return printJSON(schedule)
+}
+
+// changeAccountTier changes the tier of the account, and so the fee schedule applied to its transfers.
+func changeAccountTier(s store.Store, accountID int64, tier string) error {
+	if accountID <= 0 || tier == "" {
+		return errors.New("--account-id and --tier are required")
+	}
+
+	account, err := s.UpdateAccountTier(context.Background(), simplebanksql.UpdateAccountTierParams{
+		ID:   accountID,
+		Tier: simplebanksql.AccountTier(tier),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to change account tier: %w", err)
+	}
+
+	return printJSON(account)
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/orlandorode97/simple-bank/pkg/money"
+	"github.com/orlandorode97/simple-bank/store"
+)
+
+// createInterestPlanCommand lets operators create interest plans:
+// simplebank create-interest-plan --name=savings --tiers=0:0.01,100000:0.02
+const createInterestPlanCommand = "create-interest-plan"
+
+// createInterestPlan creates the interest plan and prints it to stdout.
+func createInterestPlan(s store.Store, name, tiers string) error {
+	if name == "" || tiers == "" {
+		return errors.New("--name and --tiers are required")
+	}
+
+	parsed, err := parseTiers(tiers)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.CreateInterestPlanTx(context.Background(), store.CreateInterestPlanTxParams{
+		Name:  name,
+		Tiers: parsed,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create interest plan: %w", err)
+	}
+
+	return printJSON(result)
+}
+
+// parseTiers parses min_balance:annual_rate pairs, min balances must be ascending and start at zero or above.
+func parseTiers(tiers string) ([]money.Tier, error) {
+	var parsed []money.Tier
+	for _, pair := range strings.Split(tiers, ",") {
+		minBalance, rate, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid tier %q, expected min_balance:annual_rate", pair)
+		}
+
+		tier := money.Tier{AnnualRate: rate}
+		var err error
+		if tier.MinBalance, err = strconv.ParseInt(minBalance, 10, 64); err != nil || tier.MinBalance < 0 {
+			return nil, fmt.Errorf("invalid min balance %q", minBalance)
+		}
+
+		if r, err := money.ParseRate(rate); err != nil || r.Sign() < 0 {
+			return nil, fmt.Errorf("invalid annual rate %q", rate)
+		}
+
+		if len(parsed) != 0 && parsed[len(parsed)-1].MinBalance >= tier.MinBalance {
+			return nil, fmt.Errorf("tier %q must start above the previous tier", pair)
+		}
+
+		parsed = append(parsed, tier)
+	}
+
+	return parsed, nil
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/orlandorode97/simple-bank/config"
 	simplebankpb "github.com/orlandorode97/simple-bank/generated/simplebank"
 	"github.com/orlandorode97/simple-bank/mail"
+	"github.com/orlandorode97/simple-bank/pkg/limits"
+	"github.com/orlandorode97/simple-bank/pkg/money"
 	"github.com/orlandorode97/simple-bank/store"
 	"github.com/orlandorode97/simple-bank/workers"
 	"github.com/spf13/pflag"
@@ -27,6 +29,25 @@ func flags() {
 	flag.String("http-addr", ":8081", "http address") // declare needed flags
 	flag.String("grpc-addr", ":8082", "grpc address")
 	flag.Duration("grpc-timeout", 5*time.Second, "grpc timeout")
+	flag.Bool("email-report", false, "email the reconciliation report to the operators, used by the reconcile command")
+	flag.Int64("account-id", 0, "account to change, used by the account-status, account-tier and overdraft-limit commands")
+	flag.String("status", "", "new account status, used by the account-status command")
+	flag.String("reason", "", "reason of the status change, used by the account-status command")
+	flag.String("operator", "", "name of the operator making the change, used by the account-status command")
+	flag.String("name", "", "name of the interest plan, used by the create-interest-plan command")
+	flag.String("tier", "", "account tier, used by the create-fee-schedule and account-tier commands")
+	flag.Int64("flat", 0, "flat fee in minor units, used by the create-fee-schedule command")
+	flag.String("percentage", "", "fee percentage of the amount such as 0.015, used by the create-fee-schedule command")
+	flag.Int64("min-fee", 0, "minimum fee in minor units, used by the create-fee-schedule command")
+	flag.Int64("max-fee", 0, "maximum fee in minor units, the fee is not capped when it is zero, used by the create-fee-schedule command")
+	flag.Int64("currency-id", 0, "currency of the fee schedule or the spending limit, used by the create-fee-schedule and spending-limit commands")
+	flag.String("username", "", "user whose limits or role are changed, used by the spending-limit and user-role commands")
+	flag.String("role", "", "new role of the user such as customer, teller or admin, used by the user-role command")
+	flag.Int64("daily-limit", -1, "daily spending limit in minor units, zero means no limit and a negative one keeps the default, used by the spending-limit command")
+	flag.Int64("monthly-limit", -1, "monthly spending limit in minor units, zero means no limit and a negative one keeps the default, used by the spending-limit command")
+	flag.Int64("limit", -1, "overdraft limit in minor units, zero removes the overdraft, used by the overdraft-limit command")
+	flag.String("keyring-file", "", "key file of the token keyring, TOKEN_KEYRING_FILE by default, used by the generate-token-keyring and rotate-token-key commands")
+	flag.String("tiers", "", "tiers of the interest plan as min_balance:annual_rate pairs such as 0:0.01,100000:0.02, used by the create-interest-plan command")
 
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine) // add standard library flags set to pflags of viper
 	pflag.Parse()                                    // Parsing pflag set
@@ -52,7 +73,17 @@ func main() {
 		log.Fatal(err)
 	}
 
-	store := store.NewSimpleBankDB(conn)
+	accountLimits, err := limits.Parse(conf.AccountSpendingLimits)
+	if err != nil {
+		log.Fatalf("invalid ACCOUNT_SPENDING_LIMITS: %v", err)
+	}
+
+	userLimits, err := limits.Parse(conf.UserSpendingLimits)
+	if err != nil {
+		log.Fatalf("invalid USER_SPENDING_LIMITS: %v", err)
+	}
+
+	store := store.NewSimpleBankDB(conn, store.SpendingLimits{Account: accountLimits, User: userLimits})
 
 	redisOpt := asynq.RedisClientOpt{
 		Addr: conf.RedisAddr,
@@ -63,9 +94,95 @@ func main() {
 
 	// Task distributor and processor
 	taskDistributor := workers.NewRedisTaskDistributor(redisOpt, suggar)
-	taskProcessor := workers.NewRedistTaskProcessor(redisOpt, store, suggar, emailSender)
+	taskProcessor := workers.NewRedistTaskProcessor(redisOpt, store, suggar, emailSender, taskDistributor)
+
+	if pflag.Arg(0) == reconcileCommand {
+		if err := reconcile(store, emailSender, conf, viper.GetBool("email-report")); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if pflag.Arg(0) == accountStatusCommand {
+		accountID, status := viper.GetInt64("account-id"), viper.GetString("status")
+		if err := changeAccountStatus(store, accountID, status, viper.GetString("reason"), viper.GetString("operator")); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	httpServer, err := simplebankhttp.NewServer(conf, store)
+	if pflag.Arg(0) == createInterestPlanCommand {
+		if err := createInterestPlan(store, viper.GetString("name"), viper.GetString("tiers")); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if pflag.Arg(0) == createFeeScheduleCommand {
+		rule := money.FeeRule{
+			Flat:       viper.GetInt64("flat"),
+			Percentage: viper.GetString("percentage"),
+			Min:        viper.GetInt64("min-fee"),
+			Max:        viper.GetInt64("max-fee"),
+		}
+		if err := createFeeSchedule(store, viper.GetInt64("currency-id"), viper.GetString("tier"), rule); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if pflag.Arg(0) == spendingLimitCommand {
+		err := overrideSpendingLimit(store, viper.GetString("username"), viper.GetInt64("currency-id"), viper.GetInt64("daily-limit"), viper.GetInt64("monthly-limit"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if pflag.Arg(0) == accountTierCommand {
+		if err := changeAccountTier(store, viper.GetInt64("account-id"), viper.GetString("tier")); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if pflag.Arg(0) == overdraftLimitCommand {
+		if err := changeOverdraftLimit(store, viper.GetInt64("account-id"), viper.GetInt64("limit")); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if pflag.Arg(0) == userRoleCommand {
+		if err := changeUserRole(store, viper.GetString("username"), viper.GetString("role")); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if pflag.Arg(0) == generateTokenKeyringCommand || pflag.Arg(0) == rotateTokenKeyCommand {
+		keyringFile := viper.GetString("keyring-file")
+		if keyringFile == "" {
+			keyringFile = conf.TokenKeyringFile
+		}
+
+		if pflag.Arg(0) == generateTokenKeyringCommand {
+			err = generateTokenKeyring(conf.TokenMaker, keyringFile)
+		} else {
+			err = rotateTokenKey(keyringFile, conf.TokenKeyGracePeriod)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	scheduler, err := workers.NewScheduler(redisOpt, suggar, conf.OperatorEmails)
+	if err != nil {
+		log.Fatalf("unable to create task scheduler: %v", err)
+	}
+
+	httpServer, err := simplebankhttp.NewServer(conf, store, taskDistributor)
 	if err != nil {
 		log.Fatalf("unable to create http server: %v", err)
 	}
@@ -98,6 +215,14 @@ func main() {
 		}
 	}()
 
+	go func() {
+		log.Printf("Serving task scheduler")
+		err := scheduler.Start()
+		if err != nil {
+			log.Fatalf("unable to start task scheduler: %v", err)
+		}
+	}()
+
 	go func() {
 		log.Printf("Serving grpc server: %v", grpcAddr)
 		err := server.Serve(tcpConn)
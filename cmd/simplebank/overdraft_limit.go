@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/store"
+)
+
+// overdraftLimitCommand lets operators set how far below zero the balance of an account can go:
+// simplebank overdraft-limit --account-id=1 --limit=50000
+// A zero limit removes the overdraft.
+const overdraftLimitCommand = "overdraft-limit"
+
+// changeOverdraftLimit sets the overdraft limit of the account and prints the account to stdout.
+func changeOverdraftLimit(s store.Store, accountID, limit int64) error {
+	if accountID <= 0 || limit < 0 {
+		return errors.New("--account-id and a --limit of zero or more are required")
+	}
+
+	account, err := s.UpdateAccountOverdraftLimit(context.Background(), simplebanksql.UpdateAccountOverdraftLimitParams{
+		ID:             accountID,
+		OverdraftLimit: limit,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to change overdraft limit: %w", err)
+	}
+
+	return printJSON(account)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/orlandorode97/simple-bank/config"
+	"github.com/orlandorode97/simple-bank/mail"
+	"github.com/orlandorode97/simple-bank/store"
+	"github.com/orlandorode97/simple-bank/workers"
+)
+
+// reconcileCommand runs the ledger reconciliation on demand: simplebank reconcile [--email-report]
+const reconcileCommand = "reconcile"
+
+// errLedgerUnbalanced makes the reconcile command exit with a non zero status when inconsistencies are found.
+var errLedgerUnbalanced = errors.New("ledger reconciliation found inconsistencies")
+
+// reconcile verifies the ledger, stores the report, and prints it to stdout.
+func reconcile(s store.Store, sender mail.EmailSender, conf config.Config, emailReport bool) error {
+	result, err := s.ReconcileTx(context.Background())
+	if err != nil {
+		return fmt.Errorf("unable to reconcile ledger: %w", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return err
+	}
+
+	if emailReport {
+		if len(conf.OperatorEmails) == 0 {
+			return errors.New("OPERATOR_EMAILS is empty, the reconciliation report was not emailed")
+		}
+
+		if err := workers.SendReconciliationReport(sender, result, conf.OperatorEmails); err != nil {
+			return err
+		}
+	}
+
+	if !result.Details.Balanced() {
+		return errLedgerUnbalanced
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/store"
+)
+
+// spendingLimitCommand lets operators override the spending limits of a user in a currency:
+// simplebank spending-limit --username=jdoe --currency-id=1 [--daily-limit=100000] [--monthly-limit=2000000]
+// A negative limit keeps the default of the currency, the override is removed when both limits are negative.
+const spendingLimitCommand = "spending-limit"
+
+// overrideSpendingLimit sets or removes the spending limits override of the user and prints it to stdout.
+func overrideSpendingLimit(s store.Store, username string, currencyID, dailyLimit, monthlyLimit int64) error {
+	if username == "" || currencyID <= 0 {
+		return errors.New("--username and --currency-id are required")
+	}
+
+	ctx := context.Background()
+	if dailyLimit < 0 && monthlyLimit < 0 {
+		err := s.DeleteSpendingLimit(ctx, simplebanksql.DeleteSpendingLimitParams{
+			Username:   username,
+			CurrencyID: currencyID,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to remove spending limit: %w", err)
+		}
+
+		fmt.Printf("spending limits of %s in currency [%d] reset to the defaults\n", username, currencyID)
+		return nil
+	}
+
+	spendingLimit, err := s.UpsertSpendingLimit(ctx, simplebanksql.UpsertSpendingLimitParams{
+		Username:     username,
+		CurrencyID:   currencyID,
+		DailyLimit:   sql.NullInt64{Int64: dailyLimit, Valid: dailyLimit >= 0},
+		MonthlyLimit: sql.NullInt64{Int64: monthlyLimit, Valid: monthlyLimit >= 0},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to override spending limit: %w", err)
+	}
+
+	return printJSON(spendingLimit)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/orlandorode97/simple-bank/pkg/token"
+)
+
+const (
+	// generateTokenKeyringCommand generates a keyring of the TOKEN_MAKER kind with a single primary key:
+	// simplebank generate-token-keyring [--keyring-file=keys.json]
+	// The keyring is printed to stdout, to be set as TOKEN_KEYRING, when there is no key file.
+	generateTokenKeyringCommand = "generate-token-keyring"
+	// rotateTokenKeyCommand generates a new primary key in the key file and retires the previous one,
+	// keys retired for longer than TOKEN_KEY_GRACE_PERIOD are removed:
+	// simplebank rotate-token-key [--keyring-file=keys.json]
+	// The servers reload the key file within a minute.
+	rotateTokenKeyCommand = "rotate-token-key"
+)
+
+// keyringSummary lists the keys of a keyring leaving out their secrets.
+type keyringSummary struct {
+	Kind    string       `json:"kind"`
+	Primary string       `json:"primary"`
+	Keys    []keySummary `json:"keys"`
+	Removed []keySummary `json:"removed,omitempty"`
+}
+
+type keySummary struct {
+	ID        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+}
+
+func newKeySummaries(keys []token.Key) []keySummary {
+	summaries := make([]keySummary, 0, len(keys))
+	for _, key := range keys {
+		summaries = append(summaries, keySummary{ID: key.ID, CreatedAt: key.CreatedAt, RetiredAt: key.RetiredAt})
+	}
+
+	return summaries
+}
+
+// generateTokenKeyring writes a new keyring to the key file, an existing key file is never overwritten.
+func generateTokenKeyring(kind, path string) error {
+	if kind == "" {
+		kind = token.KindPaseto
+	}
+
+	keyring, err := token.NewKeyring(kind, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return printJSON(keyring)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("key file %s already exists, use the %s command", path, rotateTokenKeyCommand)
+	}
+
+	if err := keyring.Save(path); err != nil {
+		return fmt.Errorf("unable to save keyring: %w", err)
+	}
+
+	return printJSON(keyringSummary{Kind: keyring.Kind, Primary: keyring.Primary, Keys: newKeySummaries(keyring.Keys)})
+}
+
+// rotateTokenKey rotates the primary key of the key file and prints the keys left and the ones removed.
+func rotateTokenKey(path string, gracePeriod time.Duration) error {
+	if path == "" {
+		return errors.New("--keyring-file or TOKEN_KEYRING_FILE is required")
+	}
+
+	keyring, err := token.LoadKeyring(path)
+	if err != nil {
+		return fmt.Errorf("unable to load keyring: %w", err)
+	}
+
+	now := time.Now()
+	// Keys whose grace period already ended are reported as removed, Rotate prunes them as well.
+	removed := keyring.Prune(now, gracePeriod)
+	if _, err := keyring.Rotate(now, gracePeriod); err != nil {
+		return fmt.Errorf("unable to rotate key: %w", err)
+	}
+
+	if err := keyring.Save(path); err != nil {
+		return fmt.Errorf("unable to save keyring: %w", err)
+	}
+
+	return printJSON(keyringSummary{
+		Kind:    keyring.Kind,
+		Primary: keyring.Primary,
+		Keys:    newKeySummaries(keyring.Keys),
+		Removed: newKeySummaries(removed),
+	})
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/policy"
+	"github.com/orlandorode97/simple-bank/store"
+)
+
+// userRoleCommand lets operators change the role of any user, it is how the first admin is made:
+// simplebank user-role --username=jdoe --role=admin
+const userRoleCommand = "user-role"
+
+// changeUserRole changes the role of the user and prints it to stdout.
+func changeUserRole(s store.Store, username, role string) error {
+	if username == "" || role == "" {
+		return errors.New("--username and --role are required")
+	}
+
+	if _, err := policy.ParseRole(role); err != nil {
+		return err
+	}
+
+	user, err := s.UpdateUserRole(context.Background(), simplebanksql.UpdateUserRoleParams{
+		Username: username,
+		Role:     simplebanksql.UserRole(role),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to change user role: %w", err)
+	}
+
+	fmt.Printf("user %s is now %s, it applies from the next login or token refresh\n", user.Username, user.Role)
+	return nil
+}
@@ -7,16 +7,31 @@ import (
 )
 
 type Config struct {
-	DBDriver             string        `mapstructure:"DB_DRIVER"`
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	SymmetricKey         string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	TokenDuration        time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	TokenRefreshDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
-	Environment          string        `mapstructure:"ENVIRONMENT"`
-	RedisAddr            string        `mapstructure:"REDIS_ADDRESS"`
-	GmailName            string        `mapstructure:"GMAIL_NAME"`
-	GmailAddress         string        `mapstructure:"GMAIL_ADDRESS"`
-	GmailPassword        string        `mapstructure:"GMAIL_PASSWORD"`
+	DBDriver               string        `mapstructure:"DB_DRIVER"`
+	DBSource               string        `mapstructure:"DB_SOURCE"`
+	SymmetricKey           string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenDuration          time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	TokenRefreshDuration   time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	Environment            string        `mapstructure:"ENVIRONMENT"`
+	RedisAddr              string        `mapstructure:"REDIS_ADDRESS"`
+	GmailName              string        `mapstructure:"GMAIL_NAME"`
+	GmailAddress           string        `mapstructure:"GMAIL_ADDRESS"`
+	GmailPassword          string        `mapstructure:"GMAIL_PASSWORD"`
+	IdempotencyKeyDuration time.Duration `mapstructure:"IDEMPOTENCY_KEY_DURATION"`
+	HoldDuration           time.Duration `mapstructure:"HOLD_DURATION"`
+	OperatorEmails         []string      `mapstructure:"OPERATOR_EMAILS"`
+	// TokenMaker is paseto, jwt or eddsa, TokenPrivateKey is the base64 encoded Ed25519 seed of the eddsa maker.
+	TokenMaker      string `mapstructure:"TOKEN_MAKER"`
+	TokenPrivateKey string `mapstructure:"TOKEN_PRIVATE_KEY"`
+	// AccountSpendingLimits and UserSpendingLimits are the default limits of every currency
+	// such as USD:daily=100000:monthly=2000000,EUR:daily=90000, currencies left out are not limited.
+	AccountSpendingLimits string `mapstructure:"ACCOUNT_SPENDING_LIMITS"`
+	UserSpendingLimits    string `mapstructure:"USER_SPENDING_LIMITS"`
+	// TokenKeyringFile and TokenKeyring replace the single token keys by a keyring of rotated keys, tokens signed
+	// with a retired key are accepted during TokenKeyGracePeriod.
+	TokenKeyringFile    string        `mapstructure:"TOKEN_KEYRING_FILE"`
+	TokenKeyring        string        `mapstructure:"TOKEN_KEYRING"`
+	TokenKeyGracePeriod time.Duration `mapstructure:"TOKEN_KEY_GRACE_PERIOD"`
 }
 
 func LoadConfig(path string) (conf Config, err error) {
@@ -13,7 +13,7 @@ const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency_id, createad_at
+RETURNING id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number
 `
 
 type AddAccountBalanceParams struct {
@@ -30,27 +30,74 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.CurrencyID,
 		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
+const addAccountHeldBalance = `-- name: AddAccountHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number
+`
+
+type AddAccountHeldBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, addAccountHeldBalance, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.CurrencyID,
+		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
 	)
 	return i, err
 }
 
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
-  owner, balance, currency_id
+  owner, balance, currency_id, name
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 )
-RETURNING id, owner, balance, currency_id, createad_at
+RETURNING id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number
 `
 
 type CreateAccountParams struct {
 	Owner      string `json:"owner"`
 	Balance    int64  `json:"balance"`
 	CurrencyID int64  `json:"currency_id"`
+	Name       string `json:"name"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.CurrencyID)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.CurrencyID,
+		arg.Name,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -58,22 +105,102 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.CurrencyID,
 		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1
+const createAccountStatusChange = `-- name: CreateAccountStatusChange :one
+INSERT INTO account_status_changes (
+  account_id, from_status, to_status, changed_by, reason
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, account_id, from_status, to_status, changed_by, reason, createad_at
 `
 
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteAccount, id)
-	return err
+type CreateAccountStatusChangeParams struct {
+	AccountID  int64         `json:"account_id"`
+	FromStatus AccountStatus `json:"from_status"`
+	ToStatus   AccountStatus `json:"to_status"`
+	ChangedBy  string        `json:"changed_by"`
+	Reason     string        `json:"reason"`
+}
+
+func (q *Queries) CreateAccountStatusChange(ctx context.Context, arg CreateAccountStatusChangeParams) (AccountStatusChange, error) {
+	row := q.db.QueryRowContext(ctx, createAccountStatusChange,
+		arg.AccountID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.ChangedBy,
+		arg.Reason,
+	)
+	var i AccountStatusChange
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.ChangedBy,
+		&i.Reason,
+		&i.CreateadAt,
+	)
+	return i, err
+}
+
+const createSystemAccounts = `-- name: CreateSystemAccounts :many
+INSERT INTO accounts (owner, balance, currency_id, system_kind)
+SELECT 'system_' || kind, 0, $1::bigint, kind
+FROM unnest(enum_range(NULL::system_account_kind)) AS kind
+RETURNING id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number
+`
+
+func (q *Queries) CreateSystemAccounts(ctx context.Context, currencyID int64) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, createSystemAccounts, currencyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.CurrencyID,
+			&i.CreateadAt,
+			&i.OverdraftLimit,
+			&i.HeldBalance,
+			&i.Status,
+			&i.SystemKind,
+			&i.InterestPlanID,
+			&i.Tier,
+			&i.Name,
+			&i.AccountNumber,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency_id, createad_at FROM accounts
+SELECT id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -86,12 +213,46 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.CurrencyID,
 		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
+const getAccountByNumber = `-- name: GetAccountByNumber :one
+SELECT id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number FROM accounts
+WHERE account_number = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByNumber, accountNumber)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.CurrencyID,
+		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency_id, createad_at FROM accounts
+SELECT id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -105,26 +266,111 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.CurrencyID,
 		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
 	)
 	return i, err
 }
 
+const getSystemAccount = `-- name: GetSystemAccount :one
+SELECT id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number FROM accounts
+WHERE system_kind = $1 AND currency_id = $2 LIMIT 1
+`
+
+type GetSystemAccountParams struct {
+	SystemKind NullSystemAccountKind `json:"system_kind"`
+	CurrencyID int64                 `json:"currency_id"`
+}
+
+func (q *Queries) GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getSystemAccount, arg.SystemKind, arg.CurrencyID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.CurrencyID,
+		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
+const listAccountStatusChanges = `-- name: ListAccountStatusChanges :many
+SELECT id, account_id, from_status, to_status, changed_by, reason, createad_at FROM account_status_changes
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListAccountStatusChangesParams struct {
+	AccountID int64 `json:"account_id"`
+	Limit     int32 `json:"limit"`
+	Offset    int32 `json:"offset"`
+}
+
+func (q *Queries) ListAccountStatusChanges(ctx context.Context, arg ListAccountStatusChangesParams) ([]AccountStatusChange, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountStatusChanges, arg.AccountID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AccountStatusChange{}
+	for rows.Next() {
+		var i AccountStatusChange
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.ChangedBy,
+			&i.Reason,
+			&i.CreateadAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency_id, createad_at FROM accounts
-WHERE owner = $1
-ORDER BY id
+SELECT a.id, a.owner, a.balance, a.currency_id, a.createad_at, a.overdraft_limit, a.held_balance, a.status, a.system_kind, a.interest_plan_id, a.tier, a.name, a.account_number FROM accounts a
+JOIN account_members m ON m.account_id = a.id
+WHERE m.username = $1 AND m.accepted_at IS NOT NULL
+ORDER BY a.currency_id, a.id
 LIMIT $2
 OFFSET $3
 `
 
 type ListAccountsParams struct {
-	Owner  string `json:"owner"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+	Username   string `json:"username"`
+	PageLimit  int32  `json:"page_limit"`
+	PageOffset int32  `json:"page_offset"`
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Username, arg.PageLimit, arg.PageOffset)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +384,14 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.CurrencyID,
 			&i.CreateadAt,
+			&i.OverdraftLimit,
+			&i.HeldBalance,
+			&i.Status,
+			&i.SystemKind,
+			&i.InterestPlanID,
+			&i.Tier,
+			&i.Name,
+			&i.AccountNumber,
 		); err != nil {
 			return nil, err
 		}
@@ -152,20 +406,119 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 	return items, nil
 }
 
-const updateAccount = `-- name: UpdateAccount :one
+const updateAccountName = `-- name: UpdateAccountName :one
+UPDATE accounts
+SET name = $2
+WHERE id = $1
+RETURNING id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number
+`
+
+type UpdateAccountNameParams struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) UpdateAccountName(ctx context.Context, arg UpdateAccountNameParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountName, arg.ID, arg.Name)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.CurrencyID,
+		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $2
+WHERE id = $1
+RETURNING id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	ID             int64 `json:"id"`
+	OverdraftLimit int64 `json:"overdraft_limit"`
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.ID, arg.OverdraftLimit)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.CurrencyID,
+		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $2
+WHERE id = $1
+RETURNING id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number
+`
+
+type UpdateAccountStatusParams struct {
+	ID     int64         `json:"id"`
+	Status AccountStatus `json:"status"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.ID, arg.Status)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.CurrencyID,
+		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
+const updateAccountTier = `-- name: UpdateAccountTier :one
 UPDATE accounts
-SET balance = $2
+SET tier = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency_id, createad_at
+RETURNING id, owner, balance, currency_id, createad_at, overdraft_limit, held_balance, status, system_kind, interest_plan_id, tier, name, account_number
 `
 
-type UpdateAccountParams struct {
-	ID      int64 `json:"id"`
-	Balance int64 `json:"balance"`
+type UpdateAccountTierParams struct {
+	ID   int64       `json:"id"`
+	Tier AccountTier `json:"tier"`
 }
 
-func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, updateAccount, arg.ID, arg.Balance)
+func (q *Queries) UpdateAccountTier(ctx context.Context, arg UpdateAccountTierParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountTier, arg.ID, arg.Tier)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -173,6 +526,14 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.CurrencyID,
 		&i.CreateadAt,
+		&i.OverdraftLimit,
+		&i.HeldBalance,
+		&i.Status,
+		&i.SystemKind,
+		&i.InterestPlanID,
+		&i.Tier,
+		&i.Name,
+		&i.AccountNumber,
 	)
 	return i, err
 }
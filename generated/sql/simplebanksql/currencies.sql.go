@@ -7,18 +7,141 @@ package simplebanksql
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createCurrency = `-- name: CreateCurrency :one
 INSERT INTO currencies (
-  name
-) VALUES ( $1 )
-RETURNING id, name
+  code, exponent, symbol, enabled
+) VALUES ( $1, $2, $3, $4 )
+RETURNING id, code, exponent, symbol, enabled, createad_at
 `
 
-func (q *Queries) CreateCurrency(ctx context.Context, name Currencies) (Currency, error) {
-	row := q.db.QueryRowContext(ctx, createCurrency, name)
+type CreateCurrencyParams struct {
+	Code     string `json:"code"`
+	Exponent int16  `json:"exponent"`
+	Symbol   string `json:"symbol"`
+	Enabled  bool   `json:"enabled"`
+}
+
+func (q *Queries) CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, createCurrency,
+		arg.Code,
+		arg.Exponent,
+		arg.Symbol,
+		arg.Enabled,
+	)
+	var i Currency
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Exponent,
+		&i.Symbol,
+		&i.Enabled,
+		&i.CreateadAt,
+	)
+	return i, err
+}
+
+const getCurrency = `-- name: GetCurrency :one
+SELECT id, code, exponent, symbol, enabled, createad_at FROM currencies
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetCurrency(ctx context.Context, id int64) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, getCurrency, id)
+	var i Currency
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Exponent,
+		&i.Symbol,
+		&i.Enabled,
+		&i.CreateadAt,
+	)
+	return i, err
+}
+
+const getCurrencyByCode = `-- name: GetCurrencyByCode :one
+SELECT id, code, exponent, symbol, enabled, createad_at FROM currencies
+WHERE code = $1 LIMIT 1
+`
+
+func (q *Queries) GetCurrencyByCode(ctx context.Context, code string) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, getCurrencyByCode, code)
+	var i Currency
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Exponent,
+		&i.Symbol,
+		&i.Enabled,
+		&i.CreateadAt,
+	)
+	return i, err
+}
+
+const listCurrencies = `-- name: ListCurrencies :many
+SELECT id, code, exponent, symbol, enabled, createad_at FROM currencies
+ORDER BY id
+`
+
+func (q *Queries) ListCurrencies(ctx context.Context) ([]Currency, error) {
+	rows, err := q.db.QueryContext(ctx, listCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Currency{}
+	for rows.Next() {
+		var i Currency
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Exponent,
+			&i.Symbol,
+			&i.Enabled,
+			&i.CreateadAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateCurrency = `-- name: UpdateCurrency :one
+UPDATE currencies
+SET
+  symbol = COALESCE($1, symbol),
+  enabled = COALESCE($2, enabled)
+WHERE
+  id = $3
+RETURNING id, code, exponent, symbol, enabled, createad_at
+`
+
+type UpdateCurrencyParams struct {
+	Symbol  sql.NullString `json:"symbol"`
+	Enabled sql.NullBool   `json:"enabled"`
+	ID      int64          `json:"id"`
+}
+
+func (q *Queries) UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, updateCurrency, arg.Symbol, arg.Enabled, arg.ID)
 	var i Currency
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Exponent,
+		&i.Symbol,
+		&i.Enabled,
+		&i.CreateadAt,
+	)
 	return i, err
 }
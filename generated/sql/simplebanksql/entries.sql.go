@@ -7,28 +7,53 @@ package simplebanksql
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
+const countAccountEntries = `-- name: CountAccountEntries :one
+SELECT COUNT(*) FROM entries
+WHERE
+  account_id = $1 AND
+  createad_at >= $2::timestamptz AND
+  createad_at < $3::timestamptz
+`
+
+type CountAccountEntriesParams struct {
+	AccountID int64     `json:"account_id"`
+	FromDate  time.Time `json:"from_date"`
+	ToDate    time.Time `json:"to_date"`
+}
+
+func (q *Queries) CountAccountEntries(ctx context.Context, arg CountAccountEntriesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAccountEntries, arg.AccountID, arg.FromDate, arg.ToDate)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
-  account_id, amount
-) VALUES ( $1, $2 )
-RETURNING id, account_id, amount, createad_at
+  account_id, amount, transfer_id
+) VALUES ( $1, $2, $3 )
+RETURNING id, account_id, amount, createad_at, transfer_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID  int64         `json:"account_id"`
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount, arg.TransferID)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreateadAt,
+		&i.TransferID,
 	)
 	return i, err
 }
@@ -43,8 +68,25 @@ func (q *Queries) DeleteEntry(ctx context.Context, id int64) error {
 	return err
 }
 
+const getAccountBalanceAt = `-- name: GetAccountBalanceAt :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS balance FROM entries
+WHERE account_id = $1 AND createad_at < $2::timestamptz
+`
+
+type GetAccountBalanceAtParams struct {
+	AccountID int64     `json:"account_id"`
+	At        time.Time `json:"at"`
+}
+
+func (q *Queries) GetAccountBalanceAt(ctx context.Context, arg GetAccountBalanceAtParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getAccountBalanceAt, arg.AccountID, arg.At)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, createad_at FROM entries
+SELECT id, account_id, amount, createad_at, transfer_id FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -56,12 +98,97 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreateadAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
+const listAccountEntries = `-- name: ListAccountEntries :many
+-- counterparty_account_id is 0 for entries that were not posted by a transfer. The running balance is summed
+-- over the whole history of the account once, before the entries are filtered and paginated.
+SELECT
+  e.id, e.account_id, e.amount, e.createad_at, e.transfer_id, e.running_balance,
+  COALESCE(
+    CASE WHEN t.from_account_id = e.account_id THEN t.to_account_id ELSE t.from_account_id END, 0
+  )::bigint AS counterparty_account_id
+FROM (
+  SELECT
+    x.id, x.account_id, x.amount, x.createad_at, x.transfer_id,
+    SUM(x.amount) OVER (PARTITION BY x.account_id ORDER BY x.id)::bigint AS running_balance
+  FROM entries x
+  WHERE x.account_id = $1
+) e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE
+  ($2::timestamptz IS NULL OR e.createad_at >= $2::timestamptz) AND
+  ($3::timestamptz IS NULL OR e.createad_at < $3::timestamptz) AND
+  ($4::text IS NULL OR
+    ($4::text = 'credit' AND e.amount > 0) OR
+    ($4::text = 'debit' AND e.amount < 0))
+ORDER BY e.id DESC
+LIMIT $5
+OFFSET $6
+`
+
+type ListAccountEntriesParams struct {
+	AccountID  int64          `json:"account_id"`
+	FromDate   sql.NullTime   `json:"from_date"`
+	ToDate     sql.NullTime   `json:"to_date"`
+	Direction  sql.NullString `json:"direction"`
+	PageLimit  int32          `json:"page_limit"`
+	PageOffset int32          `json:"page_offset"`
+}
+
+type ListAccountEntriesRow struct {
+	ID                    int64         `json:"id"`
+	AccountID             int64         `json:"account_id"`
+	Amount                int64         `json:"amount"`
+	CreateadAt            time.Time     `json:"createad_at"`
+	TransferID            sql.NullInt64 `json:"transfer_id"`
+	RunningBalance        int64         `json:"running_balance"`
+	CounterpartyAccountID int64         `json:"counterparty_account_id"`
+}
+
+func (q *Queries) ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]ListAccountEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountEntries,
+		arg.AccountID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.Direction,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountEntriesRow{}
+	for rows.Next() {
+		var i ListAccountEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreateadAt,
+			&i.TransferID,
+			&i.RunningBalance,
+			&i.CounterpartyAccountID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, createad_at FROM entries
+SELECT id, account_id, amount, createad_at, transfer_id FROM entries
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -86,6 +213,68 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreateadAt,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementEntries = `-- name: ListStatementEntries :many
+-- counterparty_account_id is 0 for entries that were not posted by a transfer.
+SELECT
+  e.id, e.account_id, e.amount, e.createad_at, e.transfer_id,
+  COALESCE(
+    CASE WHEN t.from_account_id = e.account_id THEN t.to_account_id ELSE t.from_account_id END, 0
+  )::bigint AS counterparty_account_id
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE
+  e.account_id = $1 AND
+  e.createad_at >= $2::timestamptz AND
+  e.createad_at < $3::timestamptz
+ORDER BY e.id
+`
+
+type ListStatementEntriesParams struct {
+	AccountID int64     `json:"account_id"`
+	FromDate  time.Time `json:"from_date"`
+	ToDate    time.Time `json:"to_date"`
+}
+
+type ListStatementEntriesRow struct {
+	ID                    int64         `json:"id"`
+	AccountID             int64         `json:"account_id"`
+	Amount                int64         `json:"amount"`
+	CreateadAt            time.Time     `json:"createad_at"`
+	TransferID            sql.NullInt64 `json:"transfer_id"`
+	CounterpartyAccountID int64         `json:"counterparty_account_id"`
+}
+
+func (q *Queries) ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStatementEntries, arg.AccountID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementEntriesRow{}
+	for rows.Next() {
+		var i ListStatementEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreateadAt,
+			&i.TransferID,
+			&i.CounterpartyAccountID,
 		); err != nil {
 			return nil, err
 		}
@@ -5,56 +5,320 @@
 package simplebanksql
 
 import (
+	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-type Currencies string
+type AccountMemberRole string
 
 const (
-	CurrenciesUSD Currencies = "USD"
-	CurrenciesEUR Currencies = "EUR"
-	CurrenciesMXM Currencies = "MXM"
-	CurrenciesCAD Currencies = "CAD"
-	CurrenciesJPY Currencies = "JPY"
+	AccountMemberRoleOwner      AccountMemberRole = "owner"
+	AccountMemberRoleFullAccess AccountMemberRole = "full_access"
+	AccountMemberRoleViewOnly   AccountMemberRole = "view_only"
+	AccountMemberRoleSpendLimit AccountMemberRole = "spend_limit"
 )
 
-func (e *Currencies) Scan(src interface{}) error {
+func (e *AccountMemberRole) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = Currencies(s)
+		*e = AccountMemberRole(s)
 	case string:
-		*e = Currencies(s)
+		*e = AccountMemberRole(s)
 	default:
-		return fmt.Errorf("unsupported scan type for Currencies: %T", src)
+		return fmt.Errorf("unsupported scan type for AccountMemberRole: %T", src)
 	}
 	return nil
 }
 
-type NullCurrencies struct {
-	Currencies Currencies
-	Valid      bool // Valid is true if Currencies is not NULL
+type NullAccountMemberRole struct {
+	AccountMemberRole AccountMemberRole
+	Valid             bool // Valid is true if AccountMemberRole is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullCurrencies) Scan(value interface{}) error {
+func (ns *NullAccountMemberRole) Scan(value interface{}) error {
 	if value == nil {
-		ns.Currencies, ns.Valid = "", false
+		ns.AccountMemberRole, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.Currencies.Scan(value)
+	return ns.AccountMemberRole.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullCurrencies) Value() (driver.Value, error) {
+func (ns NullAccountMemberRole) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.Currencies), nil
+	return string(ns.AccountMemberRole), nil
+}
+
+type AccountStatus string
+
+const (
+	AccountStatusActive  AccountStatus = "active"
+	AccountStatusFrozen  AccountStatus = "frozen"
+	AccountStatusDormant AccountStatus = "dormant"
+	AccountStatusClosed  AccountStatus = "closed"
+)
+
+func (e *AccountStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountStatus(s)
+	case string:
+		*e = AccountStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountStatus: %T", src)
+	}
+	return nil
+}
+
+type NullAccountStatus struct {
+	AccountStatus AccountStatus
+	Valid         bool // Valid is true if AccountStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccountStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccountStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccountStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccountStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccountStatus), nil
+}
+
+type AccountTier string
+
+const (
+	AccountTierStandard AccountTier = "standard"
+	AccountTierPremium  AccountTier = "premium"
+	AccountTierBusiness AccountTier = "business"
+)
+
+func (e *AccountTier) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountTier(s)
+	case string:
+		*e = AccountTier(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountTier: %T", src)
+	}
+	return nil
+}
+
+type NullAccountTier struct {
+	AccountTier AccountTier
+	Valid       bool // Valid is true if AccountTier is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccountTier) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccountTier, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccountTier.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccountTier) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccountTier), nil
+}
+
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferStatusActive    ScheduledTransferStatus = "active"
+	ScheduledTransferStatusPaused    ScheduledTransferStatus = "paused"
+	ScheduledTransferStatusCompleted ScheduledTransferStatus = "completed"
+	ScheduledTransferStatusCancelled ScheduledTransferStatus = "cancelled"
+)
+
+func (e *ScheduledTransferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduledTransferStatus(s)
+	case string:
+		*e = ScheduledTransferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduledTransferStatus: %T", src)
+	}
+	return nil
+}
+
+type NullScheduledTransferStatus struct {
+	ScheduledTransferStatus ScheduledTransferStatus
+	Valid                   bool // Valid is true if ScheduledTransferStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduledTransferStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduledTransferStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduledTransferStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduledTransferStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduledTransferStatus), nil
+}
+
+type SystemAccountKind string
+
+const (
+	SystemAccountKindCashIn          SystemAccountKind = "cash_in"
+	SystemAccountKindCashOut         SystemAccountKind = "cash_out"
+	SystemAccountKindSuspense        SystemAccountKind = "suspense"
+	SystemAccountKindInterestExpense SystemAccountKind = "interest_expense"
+	SystemAccountKindFeeRevenue      SystemAccountKind = "fee_revenue"
+)
+
+func (e *SystemAccountKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SystemAccountKind(s)
+	case string:
+		*e = SystemAccountKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SystemAccountKind: %T", src)
+	}
+	return nil
+}
+
+type NullSystemAccountKind struct {
+	SystemAccountKind SystemAccountKind
+	Valid             bool // Valid is true if SystemAccountKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSystemAccountKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.SystemAccountKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SystemAccountKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSystemAccountKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SystemAccountKind), nil
+}
+
+type TransferStatus string
+
+const (
+	TransferStatusPending TransferStatus = "pending"
+	TransferStatusPosted  TransferStatus = "posted"
+	TransferStatusVoided  TransferStatus = "voided"
+	TransferStatusExpired TransferStatus = "expired"
+)
+
+func (e *TransferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TransferStatus(s)
+	case string:
+		*e = TransferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TransferStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTransferStatus struct {
+	TransferStatus TransferStatus
+	Valid          bool // Valid is true if TransferStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTransferStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TransferStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TransferStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTransferStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TransferStatus), nil
+}
+
+type UserRole string
+
+const (
+	UserRoleCustomer UserRole = "customer"
+	UserRoleTeller   UserRole = "teller"
+	UserRoleAdmin    UserRole = "admin"
+)
+
+func (e *UserRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UserRole(s)
+	case string:
+		*e = UserRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UserRole: %T", src)
+	}
+	return nil
+}
+
+type NullUserRole struct {
+	UserRole UserRole
+	Valid    bool // Valid is true if UserRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUserRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.UserRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UserRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUserRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UserRole), nil
 }
 
 type Account struct {
@@ -63,11 +327,56 @@ type Account struct {
 	Balance    int64     `json:"balance"`
 	CurrencyID int64     `json:"currency_id"`
 	CreateadAt time.Time `json:"createad_at"`
+	// how far below zero the balance is allowed to go
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	// funds reserved by active holds, it lowers the available balance but not the balance
+	HeldBalance int64 `json:"held_balance"`
+	// frozen and closed accounts cannot send or receive transfers
+	Status AccountStatus `json:"status"`
+	// settlement account of the bank, deposits and withdrawals post against it
+	SystemKind     NullSystemAccountKind `json:"system_kind"`
+	InterestPlanID sql.NullInt64         `json:"interest_plan_id"`
+	Tier           AccountTier           `json:"tier"`
+	// name of the pocket, an owner can hold several accounts in the same currency
+	Name string `json:"name"`
+	// external number of the account with mod 97 check digits, clients use it instead of the id
+	AccountNumber string `json:"account_number"`
+}
+
+type AccountMember struct {
+	ID        int64             `json:"id"`
+	AccountID int64             `json:"account_id"`
+	Username  string            `json:"username"`
+	Role      AccountMemberRole `json:"role"`
+	// largest amount a spend_limit member can move in a single operation
+	SpendLimit sql.NullInt64 `json:"spend_limit"`
+	InvitedBy  string        `json:"invited_by"`
+	// the member has no access until the invitation is accepted
+	AcceptedAt sql.NullTime `json:"accepted_at"`
+	CreateadAt time.Time    `json:"createad_at"`
+}
+
+type AccountStatusChange struct {
+	ID         int64         `json:"id"`
+	AccountID  int64         `json:"account_id"`
+	FromStatus AccountStatus `json:"from_status"`
+	ToStatus   AccountStatus `json:"to_status"`
+	// username of the owner, or operator name when changed from the command line
+	ChangedBy  string    `json:"changed_by"`
+	Reason     string    `json:"reason"`
+	CreateadAt time.Time `json:"createad_at"`
 }
 
 type Currency struct {
-	ID   int64      `json:"id"`
-	Name Currencies `json:"name"`
+	ID int64 `json:"id"`
+	// ISO 4217 alphabetic code
+	Code string `json:"code"`
+	// number of decimals of the minor unit, amounts are stored in minor units
+	Exponent int16  `json:"exponent"`
+	Symbol   string `json:"symbol"`
+	// new accounts and transfers are only allowed in enabled currencies
+	Enabled    bool      `json:"enabled"`
+	CreateadAt time.Time `json:"createad_at"`
 }
 
 type Entry struct {
@@ -76,6 +385,108 @@ type Entry struct {
 	// can be negative or positive
 	Amount     int64     `json:"amount"`
 	CreateadAt time.Time `json:"createad_at"`
+	// transfer that posted the entry
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+type ExchangeRate struct {
+	ID             int64 `json:"id"`
+	FromCurrencyID int64 `json:"from_currency_id"`
+	ToCurrencyID   int64 `json:"to_currency_id"`
+	// minor units of to_currency credited per minor unit of from_currency
+	Rate        string    `json:"rate"`
+	EffectiveAt time.Time `json:"effective_at"`
+	CreateadAt  time.Time `json:"createad_at"`
+}
+
+type FeeSchedule struct {
+	ID         int64 `json:"id"`
+	CurrencyID int64 `json:"currency_id"`
+	// tier of the from account, the schedule applies to every tier without one of its own when it is null
+	AccountTier NullAccountTier `json:"account_tier"`
+	FlatAmount  int64           `json:"flat_amount"`
+	// share of the transfer amount such as 0.015 for 1.5%, added to the flat amount
+	Percentage string `json:"percentage"`
+	MinFee     int64  `json:"min_fee"`
+	// the fee is not capped when it is null
+	MaxFee     sql.NullInt64 `json:"max_fee"`
+	CreateadAt time.Time     `json:"createad_at"`
+}
+
+type Hold struct {
+	ID         int64     `json:"id"`
+	TransferID int64     `json:"transfer_id"`
+	AccountID  int64     `json:"account_id"`
+	Amount     int64     `json:"amount"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// set once the hold is captured, voided or expired
+	ReleasedAt sql.NullTime `json:"released_at"`
+	CreateadAt time.Time    `json:"createad_at"`
+}
+
+type IdempotencyKey struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+	// sha256 of the request body the key was first used with
+	RequestHash string          `json:"request_hash"`
+	TransferID  int64           `json:"transfer_id"`
+	Response    json.RawMessage `json:"response"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+	CreateadAt  time.Time       `json:"createad_at"`
+}
+
+type InterestAccrual struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	PlanID      int64     `json:"plan_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	Balance     int64     `json:"balance"`
+	// interest of the day in minor units, it is rounded once the month is capitalized
+	Amount string `json:"amount"`
+	// transfer that capitalized the accrual
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	CreateadAt time.Time     `json:"createad_at"`
+}
+
+type InterestPlan struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	CreateadAt time.Time `json:"createad_at"`
+}
+
+type InterestPlanTier struct {
+	ID         int64 `json:"id"`
+	PlanID     int64 `json:"plan_id"`
+	MinBalance int64 `json:"min_balance"`
+	// applies to the part of the balance from min_balance up to the next tier
+	AnnualRate string `json:"annual_rate"`
+}
+
+type ReconciliationReport struct {
+	ID                  int64 `json:"id"`
+	DriftedAccounts     int32 `json:"drifted_accounts"`
+	OrphanedEntries     int32 `json:"orphaned_entries"`
+	UnbalancedTransfers int32 `json:"unbalanced_transfers"`
+	// drifted accounts, orphaned entries and unbalanced transfers found
+	Details    json.RawMessage `json:"details"`
+	CreateadAt time.Time       `json:"createad_at"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	// cron expression or interval rule such as @every 24h
+	Schedule  string                  `json:"schedule"`
+	NextRunAt time.Time               `json:"next_run_at"`
+	EndAt     sql.NullTime            `json:"end_at"`
+	Status    ScheduledTransferStatus `json:"status"`
+	// consecutive runs that failed after all retries
+	FailedAttempts int32        `json:"failed_attempts"`
+	LastRunAt      sql.NullTime `json:"last_run_at"`
+	CreateadAt     time.Time    `json:"createad_at"`
 }
 
 type Session struct {
@@ -87,6 +498,23 @@ type Session struct {
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreateadAt   time.Time `json:"createad_at"`
+	// id of the session created at login, every session rotated from it shares it
+	FamilyID uuid.UUID `json:"family_id"`
+	// session rotated into this one
+	ParentID uuid.NullUUID `json:"parent_id"`
+	// when the refresh token was exchanged, presenting it again revokes the whole family
+	RotatedAt sql.NullTime `json:"rotated_at"`
+}
+
+type SpendingLimit struct {
+	ID         int64  `json:"id"`
+	Username   string `json:"username"`
+	CurrencyID int64  `json:"currency_id"`
+	// overrides the default of the currency when it is not null, zero means no limit
+	DailyLimit sql.NullInt64 `json:"daily_limit"`
+	// overrides the default of the currency when it is not null, zero means no limit
+	MonthlyLimit sql.NullInt64 `json:"monthly_limit"`
+	CreateadAt   time.Time     `json:"createad_at"`
 }
 
 type Transfer struct {
@@ -96,6 +524,21 @@ type Transfer struct {
 	// must be positive
 	Amount     int64     `json:"amount"`
 	CreateadAt time.Time `json:"createad_at"`
+	// amount credited in the currency of the to account
+	ToAmount       int64         `json:"to_amount"`
+	ExchangeRate   string        `json:"exchange_rate"`
+	ExchangeRateID sql.NullInt64 `json:"exchange_rate_id"`
+	// transfer compensated by this reversal
+	ReversedTransferID sql.NullInt64 `json:"reversed_transfer_id"`
+	// pending transfers hold funds until they are captured, voided or expired
+	Status TransferStatus `json:"status"`
+	// reference of the deposit or withdrawal in the external payment system
+	ExternalReference sql.NullString `json:"external_reference"`
+	// transfer this fee was charged for
+	ChargedTransferID sql.NullInt64 `json:"charged_transfer_id"`
+	FeeScheduleID     sql.NullInt64 `json:"fee_schedule_id"`
+	// user who initiated the transfer, only these transfers count towards the spending limits
+	InitiatedBy sql.NullString `json:"initiated_by"`
 }
 
 type User struct {
@@ -105,4 +548,6 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreateadAt        time.Time `json:"createad_at"`
+	// tellers can search users and view any account, admins can also freeze accounts, block sessions and manage the catalogs
+	Role UserRole `json:"role"`
 }
@@ -7,23 +7,85 @@ package simplebanksql
 
 import (
 	"context"
+	"database/sql"
 )
 
+const captureTransfer = `-- name: CaptureTransfer :one
+UPDATE transfers
+SET
+  amount = $1,
+  to_amount = $2,
+  status = 'posted'
+WHERE id = $3
+RETURNING id, from_account_id, to_account_id, amount, createad_at, to_amount, exchange_rate, exchange_rate_id, reversed_transfer_id, status, external_reference, charged_transfer_id, fee_schedule_id, initiated_by
+`
+
+type CaptureTransferParams struct {
+	Amount   int64 `json:"amount"`
+	ToAmount int64 `json:"to_amount"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) CaptureTransfer(ctx context.Context, arg CaptureTransferParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, captureTransfer, arg.Amount, arg.ToAmount, arg.ID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreateadAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ExchangeRateID,
+		&i.ReversedTransferID,
+		&i.Status,
+		&i.ExternalReference,
+		&i.ChargedTransferID,
+		&i.FeeScheduleID,
+		&i.InitiatedBy,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
-  from_account_id, to_account_id, amount
-) VALUES ( $1, $2, $3 )
-RETURNING id, from_account_id, to_account_id, amount, createad_at
+  from_account_id, to_account_id, amount, to_amount, exchange_rate, exchange_rate_id, reversed_transfer_id, status, external_reference,
+  charged_transfer_id, fee_schedule_id, initiated_by
+) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12 )
+RETURNING id, from_account_id, to_account_id, amount, createad_at, to_amount, exchange_rate, exchange_rate_id, reversed_transfer_id, status, external_reference, charged_transfer_id, fee_schedule_id, initiated_by
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID      int64          `json:"from_account_id"`
+	ToAccountID        int64          `json:"to_account_id"`
+	Amount             int64          `json:"amount"`
+	ToAmount           int64          `json:"to_amount"`
+	ExchangeRate       string         `json:"exchange_rate"`
+	ExchangeRateID     sql.NullInt64  `json:"exchange_rate_id"`
+	ReversedTransferID sql.NullInt64  `json:"reversed_transfer_id"`
+	Status             TransferStatus `json:"status"`
+	ExternalReference  sql.NullString `json:"external_reference"`
+	ChargedTransferID  sql.NullInt64  `json:"charged_transfer_id"`
+	FeeScheduleID      sql.NullInt64  `json:"fee_schedule_id"`
+	InitiatedBy        sql.NullString `json:"initiated_by"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.ExchangeRateID,
+		arg.ReversedTransferID,
+		arg.Status,
+		arg.ExternalReference,
+		arg.ChargedTransferID,
+		arg.FeeScheduleID,
+		arg.InitiatedBy,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -31,6 +93,15 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreateadAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ExchangeRateID,
+		&i.ReversedTransferID,
+		&i.Status,
+		&i.ExternalReference,
+		&i.ChargedTransferID,
+		&i.FeeScheduleID,
+		&i.InitiatedBy,
 	)
 	return i, err
 }
@@ -45,8 +116,28 @@ func (q *Queries) DeleteTransfer(ctx context.Context, id int64) error {
 	return err
 }
 
+const getReversedAmounts = `-- name: GetReversedAmounts :one
+SELECT
+  COALESCE(SUM(amount), 0)::bigint AS reversed_amount,
+  COALESCE(SUM(to_amount), 0)::bigint AS refunded_amount
+FROM transfers
+WHERE reversed_transfer_id = $1::bigint
+`
+
+type GetReversedAmountsRow struct {
+	ReversedAmount int64 `json:"reversed_amount"`
+	RefundedAmount int64 `json:"refunded_amount"`
+}
+
+func (q *Queries) GetReversedAmounts(ctx context.Context, transferID int64) (GetReversedAmountsRow, error) {
+	row := q.db.QueryRowContext(ctx, getReversedAmounts, transferID)
+	var i GetReversedAmountsRow
+	err := row.Scan(&i.ReversedAmount, &i.RefundedAmount)
+	return i, err
+}
+
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, createad_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, createad_at, to_amount, exchange_rate, exchange_rate_id, reversed_transfer_id, status, external_reference, charged_transfer_id, fee_schedule_id, initiated_by FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -59,12 +150,76 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreateadAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ExchangeRateID,
+		&i.ReversedTransferID,
+		&i.Status,
+		&i.ExternalReference,
+		&i.ChargedTransferID,
+		&i.FeeScheduleID,
+		&i.InitiatedBy,
+	)
+	return i, err
+}
+
+const getTransferByExternalReference = `-- name: GetTransferByExternalReference :one
+SELECT id, from_account_id, to_account_id, amount, createad_at, to_amount, exchange_rate, exchange_rate_id, reversed_transfer_id, status, external_reference, charged_transfer_id, fee_schedule_id, initiated_by FROM transfers
+WHERE external_reference = $1 LIMIT 1
+`
+
+func (q *Queries) GetTransferByExternalReference(ctx context.Context, externalReference sql.NullString) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferByExternalReference, externalReference)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreateadAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ExchangeRateID,
+		&i.ReversedTransferID,
+		&i.Status,
+		&i.ExternalReference,
+		&i.ChargedTransferID,
+		&i.FeeScheduleID,
+		&i.InitiatedBy,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, createad_at, to_amount, exchange_rate, exchange_rate_id, reversed_transfer_id, status, external_reference, charged_transfer_id, fee_schedule_id, initiated_by FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreateadAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ExchangeRateID,
+		&i.ReversedTransferID,
+		&i.Status,
+		&i.ExternalReference,
+		&i.ChargedTransferID,
+		&i.FeeScheduleID,
+		&i.InitiatedBy,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, createad_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, createad_at, to_amount, exchange_rate, exchange_rate_id, reversed_transfer_id, status, external_reference, charged_transfer_id, fee_schedule_id, initiated_by FROM transfers
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -90,6 +245,15 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreateadAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.ExchangeRateID,
+			&i.ReversedTransferID,
+			&i.Status,
+			&i.ExternalReference,
+			&i.ChargedTransferID,
+			&i.FeeScheduleID,
+			&i.InitiatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -103,3 +267,37 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	}
 	return items, nil
 }
+
+const updateTransferStatus = `-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET status = $2
+WHERE id = $1
+RETURNING id, from_account_id, to_account_id, amount, createad_at, to_amount, exchange_rate, exchange_rate_id, reversed_transfer_id, status, external_reference, charged_transfer_id, fee_schedule_id, initiated_by
+`
+
+type UpdateTransferStatusParams struct {
+	ID     int64          `json:"id"`
+	Status TransferStatus `json:"status"`
+}
+
+func (q *Queries) UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, updateTransferStatus, arg.ID, arg.Status)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreateadAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ExchangeRateID,
+		&i.ReversedTransferID,
+		&i.Status,
+		&i.ExternalReference,
+		&i.ChargedTransferID,
+		&i.FeeScheduleID,
+		&i.InitiatedBy,
+	)
+	return i, err
+}
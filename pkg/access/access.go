@@ -0,0 +1,78 @@
+// Package access decides what the members of an account can do with it.
+package access
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrForbidden is returned when the role of the member does not allow the action,
+// or the amount is above the spend limit of the member.
+var ErrForbidden = errors.New("action not allowed for the account member")
+
+// Role is the role of a member of an account.
+type Role string
+
+const (
+	// RoleOwner can do everything, including managing the account and its members.
+	RoleOwner Role = "owner"
+	// RoleFullAccess can view the account and move any amount, but can't manage it.
+	RoleFullAccess Role = "full_access"
+	// RoleViewOnly can only view the account.
+	RoleViewOnly Role = "view_only"
+	// RoleSpendLimit can view the account and move amounts up to its spend limit.
+	RoleSpendLimit Role = "spend_limit"
+)
+
+// Action is something a member does with an account.
+type Action string
+
+const (
+	// ActionView reads the account, its entries and its statements.
+	ActionView Action = "view"
+	// ActionReceive handles funds coming into the account, such as deposits or captures of received transfers.
+	ActionReceive Action = "receive"
+	// ActionSpend moves funds out of the account.
+	ActionSpend Action = "spend"
+	// ActionManage changes the account itself, such as its status, name, plan or members.
+	ActionManage Action = "manage"
+)
+
+// roleActions stores the actions every role is allowed to do.
+var roleActions = map[Role][]Action{
+	RoleOwner:      {ActionView, ActionReceive, ActionSpend, ActionManage},
+	RoleFullAccess: {ActionView, ActionReceive, ActionSpend},
+	RoleSpendLimit: {ActionView, ActionReceive, ActionSpend},
+	RoleViewOnly:   {ActionView},
+}
+
+// Member is the access of a user to an account.
+type Member struct {
+	Role Role
+	// SpendLimit is the largest amount a spend limit member can move in a single operation.
+	SpendLimit int64
+}
+
+// Authorize verifies the member can do the action, amount is only checked when spending.
+func (m Member) Authorize(action Action, amount int64) error {
+	if !m.Role.Allows(action) {
+		return fmt.Errorf("%w: %s members can't %s", ErrForbidden, m.Role, action)
+	}
+
+	if action == ActionSpend && m.Role == RoleSpendLimit && amount > m.SpendLimit {
+		return fmt.Errorf("%w: %d is above the spend limit of %d", ErrForbidden, amount, m.SpendLimit)
+	}
+
+	return nil
+}
+
+// Allows reports whether the role is allowed to do the action regardless of the amount.
+func (r Role) Allows(action Action) bool {
+	for _, allowed := range roleActions[r] {
+		if allowed == action {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package access
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemberAuthorize(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		member  Member
+		action  Action
+		amount  int64
+		allowed bool
+	}{
+		{desc: "owner manages", member: Member{Role: RoleOwner}, action: ActionManage, allowed: true},
+		{desc: "owner spends any amount", member: Member{Role: RoleOwner}, action: ActionSpend, amount: 1 << 40, allowed: true},
+		{desc: "full access spends", member: Member{Role: RoleFullAccess}, action: ActionSpend, amount: 1 << 40, allowed: true},
+		{desc: "full access can't manage", member: Member{Role: RoleFullAccess}, action: ActionManage},
+		{desc: "view only views", member: Member{Role: RoleViewOnly}, action: ActionView, allowed: true},
+		{desc: "view only can't receive", member: Member{Role: RoleViewOnly}, action: ActionReceive},
+		{desc: "view only can't spend", member: Member{Role: RoleViewOnly}, action: ActionSpend, amount: 1},
+		{desc: "spend limit within limit", member: Member{Role: RoleSpendLimit, SpendLimit: 500}, action: ActionSpend, amount: 500, allowed: true},
+		{desc: "spend limit above limit", member: Member{Role: RoleSpendLimit, SpendLimit: 500}, action: ActionSpend, amount: 501},
+		{desc: "spend limit can't manage", member: Member{Role: RoleSpendLimit, SpendLimit: 500}, action: ActionManage},
+		{desc: "unknown role", member: Member{Role: "guest"}, action: ActionView},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.member.Authorize(tc.action, tc.amount)
+			if tc.allowed && err != nil {
+				t.Fatalf("Authorize(%s, %d): unexpected error %v", tc.action, tc.amount, err)
+			}
+
+			if !tc.allowed && !errors.Is(err, ErrForbidden) {
+				t.Fatalf("Authorize(%s, %d): got %v want %v", tc.action, tc.amount, err, ErrForbidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package accountnumber validates the external account numbers of the bank.
+//
+// An account number has the layout of an IBAN: the SB prefix, two check digits and a 12 digit basic number,
+// such as SB68 1234 5678 9012. The check digits are computed with ISO 7064 mod 97-10, so a single mistyped
+// digit or two swapped digits are always detected.
+package accountnumber
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// Prefix starts every account number.
+	Prefix = "SB"
+	// basicLength is the number of digits of the basic account number.
+	basicLength = 12
+	// Length is the number of characters of an account number without spaces.
+	Length = len(Prefix) + 2 + basicLength
+)
+
+// ErrInvalid is returned when an account number is malformed or its check digits do not match.
+var ErrInvalid = errors.New("invalid account number")
+
+// Normalize removes the spaces of the account number and uppercases it.
+func Normalize(number string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(number), ""))
+}
+
+// Validate verifies the layout and the check digits of a normalized account number.
+func Validate(number string) error {
+	if len(number) != Length || !strings.HasPrefix(number, Prefix) {
+		return fmt.Errorf("%w: %q must be %s followed by %d digits", ErrInvalid, number, Prefix, Length-len(Prefix))
+	}
+
+	for _, r := range number[len(Prefix):] {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("%w: %q must be %s followed by %d digits", ErrInvalid, number, Prefix, Length-len(Prefix))
+		}
+	}
+
+	if mod97(rearrange(number)) != 1 {
+		return fmt.Errorf("%w: %q check digits do not match", ErrInvalid, number)
+	}
+
+	return nil
+}
+
+// New returns the account number of a basic number of 12 digits, computing its check digits.
+func New(basic string) (string, error) {
+	number := Prefix + "00" + basic
+	if len(number) != Length {
+		return "", fmt.Errorf("%w: basic number %q must have %d digits", ErrInvalid, basic, basicLength)
+	}
+
+	for _, r := range basic {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: basic number %q must have %d digits", ErrInvalid, basic, basicLength)
+		}
+	}
+
+	check := 98 - mod97(rearrange(number))
+	return fmt.Sprintf("%s%02d%s", Prefix, check, basic), nil
+}
+
+// Format groups the account number in blocks of four characters to be read by people.
+func Format(number string) string {
+	var b strings.Builder
+	for i, r := range number {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// rearrange moves the prefix and the check digits to the end and converts the letters to digits, A is 10 and Z is 35.
+func rearrange(number string) string {
+	var b strings.Builder
+	for _, r := range number[4:] + number[:4] {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&b, "%d", r-'A'+10)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// mod97 returns digits mod 97 without converting them to an integer, they don't fit in an int64.
+func mod97(digits string) int {
+	var remainder int
+	for _, r := range digits {
+		remainder = (remainder*10 + int(r-'0')) % 97
+	}
+
+	return remainder
+}
@@ -0,0 +1,78 @@
+package accountnumber
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	number, err := New("123456789012")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Validate(number); err != nil {
+		t.Errorf("Validate(%q): %v", number, err)
+	}
+
+	if _, err := New("12345"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("New short basic number: got %v want %v", err, ErrInvalid)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid, err := New("000000000042")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Swaps the last two digits.
+	swapped := valid[:Length-2] + valid[Length-1:] + valid[Length-2:Length-1]
+
+	tcs := []struct {
+		desc   string
+		number string
+		valid  bool
+	}{
+		{desc: "valid", number: valid, valid: true},
+		{desc: "mistyped digit", number: valid[:Length-1] + "3"},
+		{desc: "swapped digits", number: swapped},
+		{desc: "wrong prefix", number: "XX" + valid[2:]},
+		{desc: "letters", number: valid[:Length-1] + "A"},
+		{desc: "too short", number: valid[:Length-1]},
+		{desc: "empty"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := Validate(tc.number)
+			if tc.valid && err != nil {
+				t.Fatalf("Validate(%q): unexpected error %v", tc.number, err)
+			}
+
+			if !tc.valid && !errors.Is(err, ErrInvalid) {
+				t.Fatalf("Validate(%q): got %v want %v", tc.number, err, ErrInvalid)
+			}
+		})
+	}
+}
+
+func TestNormalizeFormat(t *testing.T) {
+	number, err := New("123456789012")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formatted := Format(number)
+	if len(formatted) != Length+3 {
+		t.Errorf("Format(%q): got %q", number, formatted)
+	}
+
+	if got := Normalize(" " + formatted + " "); got != number {
+		t.Errorf("Normalize(%q): got %q want %q", formatted, got, number)
+	}
+
+	if got := Normalize("sb00 0000"); got != "SB000000" {
+		t.Errorf("Normalize lowercase: got %q", got)
+	}
+}
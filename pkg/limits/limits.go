@@ -0,0 +1,123 @@
+// Package limits caps the outgoing volume of accounts and users over rolling windows.
+package limits
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is a rolling period the outgoing volume is summed over.
+type Window string
+
+const (
+	// Daily sums the last 24 hours.
+	Daily Window = "daily"
+	// Monthly sums the last 30 days.
+	Monthly Window = "monthly"
+)
+
+// Since returns the start of the window ending at now.
+func (w Window) Since(now time.Time) time.Time {
+	if w == Monthly {
+		return now.AddDate(0, 0, -30)
+	}
+
+	return now.Add(-24 * time.Hour)
+}
+
+// Limits stores the largest outgoing volume of every window in minor units, zero means no limit.
+type Limits struct {
+	Daily   int64 `json:"daily"`
+	Monthly int64 `json:"monthly"`
+}
+
+// Usage stores the outgoing volume already spent in every window.
+type Usage struct {
+	Daily   int64 `json:"daily"`
+	Monthly int64 `json:"monthly"`
+}
+
+// Status reports a window of a limit, Remaining is only meaningful when the window is limited.
+type Status struct {
+	Window    Window `json:"window"`
+	Limited   bool   `json:"limited"`
+	Limit     int64  `json:"limit"`
+	Used      int64  `json:"used"`
+	Remaining int64  `json:"remaining"`
+}
+
+// Report returns the status of every window given the usage.
+func (l Limits) Report(usage Usage) []Status {
+	return []Status{
+		status(Daily, l.Daily, usage.Daily),
+		status(Monthly, l.Monthly, usage.Monthly),
+	}
+}
+
+// Check returns the status of the first window that amount would exceed, or nil when every window has room for it.
+func (l Limits) Check(usage Usage, amount int64) *Status {
+	for _, s := range l.Report(usage) {
+		if s.Limited && amount > s.Remaining {
+			return &s
+		}
+	}
+
+	return nil
+}
+
+func status(window Window, limit, used int64) Status {
+	s := Status{Window: window, Limited: limit > 0, Limit: limit, Used: used}
+	if s.Limited && used < limit {
+		s.Remaining = limit - used
+	}
+
+	return s
+}
+
+// Parse parses the limits of every currency such as "USD:daily=100000:monthly=2000000,EUR:daily=50000".
+// Windows left out are not limited.
+func Parse(s string) (map[string]Limits, error) {
+	limits := make(map[string]Limits)
+	if strings.TrimSpace(s) == "" {
+		return limits, nil
+	}
+
+	for _, currencyLimits := range strings.Split(s, ",") {
+		fields := strings.Split(strings.TrimSpace(currencyLimits), ":")
+		currency := strings.ToUpper(fields[0])
+		if currency == "" {
+			return nil, fmt.Errorf("missing currency in %q", currencyLimits)
+		}
+
+		if _, ok := limits[currency]; ok {
+			return nil, fmt.Errorf("duplicated currency %s", currency)
+		}
+
+		var l Limits
+		for _, field := range fields[1:] {
+			window, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid limit %q of %s", field, currency)
+			}
+
+			amount, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || amount < 0 {
+				return nil, fmt.Errorf("invalid amount %q of %s %s limit", value, currency, window)
+			}
+
+			switch Window(window) {
+			case Daily:
+				l.Daily = amount
+			case Monthly:
+				l.Monthly = amount
+			default:
+				return nil, fmt.Errorf("unknown window %q of %s", window, currency)
+			}
+		}
+		limits[currency] = l
+	}
+
+	return limits, nil
+}
@@ -0,0 +1,86 @@
+package limits
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse("USD:daily=100000:monthly=2000000, eur:monthly=50000,JPY")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]Limits{
+		"USD": {Daily: 100000, Monthly: 2000000},
+		"EUR": {Monthly: 50000},
+		"JPY": {},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse: got %v want %v", got, want)
+	}
+
+	if got, err := Parse(""); err != nil || len(got) != 0 {
+		t.Errorf("Parse empty: got %v, %v", got, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		":daily=1",
+		"USD:daily",
+		"USD:daily=-1",
+		"USD:weekly=1",
+		"USD:daily=1,USD:monthly=2",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): expected an error", s)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	l := Limits{Daily: 1000, Monthly: 5000}
+
+	tcs := []struct {
+		desc   string
+		usage  Usage
+		amount int64
+		want   *Status
+	}{
+		{desc: "within limits", usage: Usage{Daily: 500, Monthly: 500}, amount: 500},
+		{desc: "daily exceeded", usage: Usage{Daily: 600, Monthly: 600}, amount: 500,
+			want: &Status{Window: Daily, Limited: true, Limit: 1000, Used: 600, Remaining: 400}},
+		{desc: "monthly exceeded", usage: Usage{Daily: 0, Monthly: 4800}, amount: 500,
+			want: &Status{Window: Monthly, Limited: true, Limit: 5000, Used: 4800, Remaining: 200}},
+		{desc: "already over the limit", usage: Usage{Daily: 1200, Monthly: 1200}, amount: 1,
+			want: &Status{Window: Daily, Limited: true, Limit: 1000, Used: 1200, Remaining: 0}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := l.Check(tc.usage, tc.amount)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Check: got %+v want %+v", got, tc.want)
+			}
+		})
+	}
+
+	if got := (Limits{}).Check(Usage{Daily: 1 << 40}, 1<<40); got != nil {
+		t.Errorf("Check without limits: got %+v want nil", got)
+	}
+}
+
+func TestWindowSince(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	if got, want := Daily.Since(now), time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Daily.Since: got %v want %v", got, want)
+	}
+
+	if got, want := Monthly.Since(now), time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Monthly.Since: got %v want %v", got, want)
+	}
+}
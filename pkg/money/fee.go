@@ -0,0 +1,50 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+)
+
+// FeeRule computes the fee of a transfer amount: a flat amount plus a percentage of the amount,
+// kept between a minimum and a maximum.
+type FeeRule struct {
+	Flat int64
+	// Percentage is a decimal share of the amount such as "0.015" for 1.5%.
+	Percentage string
+	Min        int64
+	// Max caps the fee, the fee is not capped when it is zero.
+	Max int64
+}
+
+// Apply returns the fee of amount, the percentage is rounded half away from zero before the caps are applied.
+func (r FeeRule) Apply(amount int64) (int64, error) {
+	if amount < 0 {
+		return 0, errors.New("fee of a negative amount")
+	}
+
+	percentage := "0"
+	if r.Percentage != "" {
+		percentage = r.Percentage
+	}
+
+	rate, err := ParseRate(percentage)
+	if err != nil {
+		return 0, err
+	}
+
+	variable, err := Round(new(big.Rat).Mul(rate, new(big.Rat).SetInt64(amount)))
+	if err != nil {
+		return 0, err
+	}
+
+	fee := r.Flat + variable
+	if fee < r.Min {
+		fee = r.Min
+	}
+
+	if r.Max != 0 && fee > r.Max {
+		fee = r.Max
+	}
+
+	return fee, nil
+}
@@ -0,0 +1,43 @@
+package money
+
+import "testing"
+
+func TestFeeRuleApply(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		rule   FeeRule
+		amount int64
+		want   int64
+	}{
+		{desc: "no fee", rule: FeeRule{}, amount: 10000, want: 0},
+		{desc: "flat", rule: FeeRule{Flat: 150}, amount: 10000, want: 150},
+		{desc: "percentage", rule: FeeRule{Percentage: "0.015"}, amount: 10000, want: 150},
+		{desc: "percentage rounds half away from zero", rule: FeeRule{Percentage: "0.015"}, amount: 100, want: 2},
+		{desc: "flat plus percentage", rule: FeeRule{Flat: 25, Percentage: "0.01"}, amount: 10000, want: 125},
+		{desc: "minimum", rule: FeeRule{Percentage: "0.01", Min: 50}, amount: 1000, want: 50},
+		{desc: "maximum", rule: FeeRule{Percentage: "0.01", Max: 500}, amount: 1000000, want: 500},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tc.rule.Apply(tc.amount)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tc.want {
+				t.Errorf("Apply(%d): got %d want %d", tc.amount, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFeeRuleApplyErrors(t *testing.T) {
+	if _, err := (FeeRule{Percentage: "not-a-rate"}).Apply(100); err == nil {
+		t.Error("expected an error for an invalid percentage")
+	}
+
+	if _, err := (FeeRule{}).Apply(-100); err == nil {
+		t.Error("expected an error for a negative amount")
+	}
+}
@@ -0,0 +1,53 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Tier is a band of an interest plan, its rate applies to the part of the balance
+// from MinBalance up to the MinBalance of the next tier.
+type Tier struct {
+	MinBalance int64
+	// AnnualRate is a decimal rate such as "0.035" for 3.5% a year.
+	AnnualRate string
+}
+
+// DailyInterest returns the interest earned by balance in one day, every band of the balance earns the rate of its tier.
+// Tiers must be sorted by MinBalance ascending, and balances below the first tier or negative earn nothing.
+func DailyInterest(balance int64, tiers []Tier, daysInYear int) (*big.Rat, error) {
+	if daysInYear <= 0 {
+		return nil, errors.New("days in year must be positive")
+	}
+
+	interest := new(big.Rat)
+	for i, tier := range tiers {
+		if balance <= tier.MinBalance {
+			break
+		}
+
+		upper := balance
+		if i+1 < len(tiers) && tiers[i+1].MinBalance < balance {
+			upper = tiers[i+1].MinBalance
+		}
+
+		rate, err := ParseRate(tier.AnnualRate)
+		if err != nil {
+			return nil, err
+		}
+
+		band := new(big.Rat).SetInt64(upper - tier.MinBalance)
+		interest.Add(interest, band.Mul(band, rate))
+	}
+
+	return interest.Quo(interest, new(big.Rat).SetInt64(int64(daysInYear))), nil
+}
+
+// DaysInYear returns 366 for leap years and 365 otherwise.
+func DaysInYear(year int) int {
+	if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+		return 366
+	}
+
+	return 365
+}
@@ -0,0 +1,53 @@
+package money
+
+import "testing"
+
+func TestDailyInterest(t *testing.T) {
+	tiers := []Tier{
+		{MinBalance: 0, AnnualRate: "0.01"},
+		{MinBalance: 100000, AnnualRate: "0.02"},
+		{MinBalance: 1000000, AnnualRate: "0.03"},
+	}
+
+	tcs := []struct {
+		desc    string
+		balance int64
+		want    string
+	}{
+		{desc: "negative balance", balance: -5000, want: "0.0000000000"},
+		{desc: "first tier", balance: 36500, want: "1.0000000000"},
+		{desc: "two tiers", balance: 136500, want: "4.7397260274"},
+		{desc: "three tiers", balance: 1036500, want: "55.0547945205"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := DailyInterest(tc.balance, tiers, 365)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.FloatString(10) != tc.want {
+				t.Errorf("DailyInterest(%d): got %s want %s", tc.balance, got.FloatString(10), tc.want)
+			}
+		})
+	}
+}
+
+func TestDailyInterestErrors(t *testing.T) {
+	if _, err := DailyInterest(100, []Tier{{AnnualRate: "not-a-rate"}}, 365); err == nil {
+		t.Error("expected an error for an invalid rate")
+	}
+
+	if _, err := DailyInterest(100, nil, 0); err == nil {
+		t.Error("expected an error for zero days in year")
+	}
+}
+
+func TestDaysInYear(t *testing.T) {
+	for year, want := range map[int]int{2023: 365, 2024: 366, 1900: 365, 2000: 366} {
+		if got := DaysInYear(year); got != want {
+			t.Errorf("DaysInYear(%d): got %d want %d", year, got, want)
+		}
+	}
+}
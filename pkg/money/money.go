@@ -0,0 +1,71 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrOverflow is returned when a calculated amount does not fit in an int64.
+var ErrOverflow = errors.New("amount overflows int64")
+
+// ParseRate parses a decimal rate such as "17.25" or "0.0005" without losing precision.
+func ParseRate(rate string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok {
+		return nil, fmt.Errorf("invalid rate %q", rate)
+	}
+
+	return r, nil
+}
+
+// ApplyRate multiplies amount by the decimal rate and rounds the result half away from zero.
+func ApplyRate(amount int64, rate string) (int64, error) {
+	r, err := ParseRate(rate)
+	if err != nil {
+		return 0, err
+	}
+
+	return Round(new(big.Rat).Mul(r, new(big.Rat).SetInt64(amount)))
+}
+
+// Round rounds x half away from zero to the nearest int64.
+func Round(x *big.Rat) (int64, error) {
+	num, den := x.Num(), x.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	// |rem| * 2 >= den means the fraction is at least one half.
+	if new(big.Int).Mul(rem.Abs(rem), big.NewInt(2)).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(int64(num.Sign())))
+	}
+
+	if !quo.IsInt64() {
+		return 0, ErrOverflow
+	}
+
+	return quo.Int64(), nil
+}
+
+// Prorate returns the share of total that corresponds to part of whole, rounded half away from zero.
+func Prorate(total, part, whole int64) (int64, error) {
+	if whole == 0 {
+		return 0, errors.New("prorate by zero")
+	}
+
+	share := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(total), big.NewInt(part)), big.NewInt(whole))
+	return Round(share)
+}
+
+// Reciprocal returns 1/rate as a decimal string with the given number of decimals.
+func Reciprocal(rate string, decimals int) (string, error) {
+	r, err := ParseRate(rate)
+	if err != nil {
+		return "", err
+	}
+
+	if r.Sign() == 0 {
+		return "", fmt.Errorf("invalid rate %q: reciprocal of zero", rate)
+	}
+
+	return new(big.Rat).Inv(r).FloatString(decimals), nil
+}
@@ -0,0 +1,68 @@
+package money
+
+import "testing"
+
+func TestApplyRate(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		amount int64
+		rate   string
+		want   int64
+	}{
+		{desc: "identity rate", amount: 1050, rate: "1", want: 1050},
+		{desc: "rounds down below half", amount: 100, rate: "0.1234", want: 12},
+		{desc: "rounds half away from zero", amount: 5, rate: "0.5", want: 3},
+		{desc: "rounds negative half away from zero", amount: -5, rate: "0.5", want: -3},
+		{desc: "high precision rate", amount: 1000000, rate: "17.1234567891", want: 17123457},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := ApplyRate(tc.amount, tc.rate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("ApplyRate(%d, %s): got %d want %d", tc.amount, tc.rate, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyRateErrors(t *testing.T) {
+	if _, err := ApplyRate(100, "not-a-rate"); err == nil {
+		t.Error("expected an error for an invalid rate")
+	}
+
+	if _, err := ApplyRate(1<<62, "4"); err != ErrOverflow {
+		t.Errorf("got %v want %v", err, ErrOverflow)
+	}
+}
+
+func TestProrate(t *testing.T) {
+	got, err := Prorate(1725, 50, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 863 {
+		t.Errorf("Prorate(1725, 50, 100): got %d want 863", got)
+	}
+
+	if _, err := Prorate(1725, 50, 0); err == nil {
+		t.Error("expected an error when prorating by zero")
+	}
+}
+
+func TestReciprocal(t *testing.T) {
+	got, err := Reciprocal("4.0000000000", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "0.2500000000" {
+		t.Errorf("Reciprocal(4): got %s want 0.2500000000", got)
+	}
+
+	if _, err := Reciprocal("0", 10); err == nil {
+		t.Error("expected an error for the reciprocal of zero")
+	}
+}
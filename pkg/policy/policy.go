@@ -0,0 +1,100 @@
+// Package policy decides what the users can do in the bank depending on their role, it is shared by the
+// http and grpc servers.
+package policy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrForbidden is returned when the role of the user does not have the permission.
+var ErrForbidden = errors.New("action not allowed for the user")
+
+// Role is the role of a user, it is embedded in the token payload.
+type Role string
+
+const (
+	// RoleCustomer only uses the accounts it is a member of, it is the role of every new user.
+	RoleCustomer Role = "customer"
+	// RoleTeller assists customers, it can search users, view any account and post deposits and withdrawals.
+	RoleTeller Role = "teller"
+	// RoleAdmin can do everything, including freezing accounts, blocking sessions and managing the catalogs.
+	RoleAdmin Role = "admin"
+)
+
+// Permission is something only the staff of the bank can do.
+type Permission string
+
+const (
+	// PermissionSearchUsers looks up any user by username, name or email.
+	PermissionSearchUsers Permission = "users:search"
+	// PermissionManageRoles changes the role of a user.
+	PermissionManageRoles Permission = "users:manage_roles"
+	// PermissionViewAccounts reads any account, whether the user is a member of it or not.
+	PermissionViewAccounts Permission = "accounts:view"
+	// PermissionFreezeAccounts freezes and unfreezes any account.
+	PermissionFreezeAccounts Permission = "accounts:freeze"
+	// PermissionManageOverdrafts sets how far below zero the balance of any account can go.
+	PermissionManageOverdrafts Permission = "accounts:manage_overdrafts"
+	// PermissionManageInterestPlans attaches interest plans to any account and detaches them.
+	PermissionManageInterestPlans Permission = "accounts:manage_interest_plans"
+	// PermissionPostSettlements posts deposits and withdrawals of funds coming from or leaving the bank on any account.
+	PermissionPostSettlements Permission = "accounts:post_settlements"
+	// PermissionBlockSessions logs out any user.
+	PermissionBlockSessions Permission = "sessions:block"
+	// PermissionManageCurrencies adds currencies to the catalog, enables and disables them.
+	PermissionManageCurrencies Permission = "currencies:manage"
+)
+
+// rolePermissions stores the permissions of every role, customers have none.
+var rolePermissions = map[Role][]Permission{
+	RoleTeller: {PermissionSearchUsers, PermissionViewAccounts, PermissionPostSettlements},
+	RoleAdmin: {
+		PermissionSearchUsers,
+		PermissionManageRoles,
+		PermissionViewAccounts,
+		PermissionFreezeAccounts,
+		PermissionManageOverdrafts,
+		PermissionManageInterestPlans,
+		PermissionPostSettlements,
+		PermissionBlockSessions,
+		PermissionManageCurrencies,
+	},
+}
+
+// ParseRole returns the role named by s, tokens issued before roles existed have no role and are customers.
+func ParseRole(s string) (Role, error) {
+	switch role := Role(s); role {
+	case RoleCustomer, RoleTeller, RoleAdmin:
+		return role, nil
+	case "":
+		return RoleCustomer, nil
+	}
+
+	return "", fmt.Errorf("unknown role %q: must be %s, %s or %s", s, RoleCustomer, RoleTeller, RoleAdmin)
+}
+
+// Authorize verifies the role named by role has the permission.
+func Authorize(role string, permission Permission) error {
+	r, err := ParseRole(role)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrForbidden, err)
+	}
+
+	if !r.Allows(permission) {
+		return fmt.Errorf("%w: %s users don't have the %s permission", ErrForbidden, r, permission)
+	}
+
+	return nil
+}
+
+// Allows reports whether the role has the permission.
+func (r Role) Allows(permission Permission) bool {
+	for _, allowed := range rolePermissions[r] {
+		if allowed == permission {
+			return true
+		}
+	}
+
+	return false
+}
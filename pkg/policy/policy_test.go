@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	tcs := []struct {
+		desc       string
+		role       string
+		permission Permission
+		allowed    bool
+	}{
+		{desc: "admin freezes accounts", role: "admin", permission: PermissionFreezeAccounts, allowed: true},
+		{desc: "admin manages currencies", role: "admin", permission: PermissionManageCurrencies, allowed: true},
+		{desc: "teller searches users", role: "teller", permission: PermissionSearchUsers, allowed: true},
+		{desc: "teller views accounts", role: "teller", permission: PermissionViewAccounts, allowed: true},
+		{desc: "teller can't freeze accounts", role: "teller", permission: PermissionFreezeAccounts},
+		{desc: "teller can't block sessions", role: "teller", permission: PermissionBlockSessions},
+		{desc: "teller can't set overdraft limits", role: "teller", permission: PermissionManageOverdrafts},
+		{desc: "admin sets overdraft limits", role: "admin", permission: PermissionManageOverdrafts, allowed: true},
+		{desc: "teller can't attach interest plans", role: "teller", permission: PermissionManageInterestPlans},
+		{desc: "admin attaches interest plans", role: "admin", permission: PermissionManageInterestPlans, allowed: true},
+		{desc: "teller posts settlements", role: "teller", permission: PermissionPostSettlements, allowed: true},
+		{desc: "customer can't post settlements", role: "customer", permission: PermissionPostSettlements},
+		{desc: "customer can't view accounts", role: "customer", permission: PermissionViewAccounts},
+		{desc: "token without role is a customer", role: "", permission: PermissionSearchUsers},
+		{desc: "unknown role", role: "root", permission: PermissionSearchUsers},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := Authorize(tc.role, tc.permission)
+			if tc.allowed && err != nil {
+				t.Fatalf("Authorize(%q, %s): unexpected error %v", tc.role, tc.permission, err)
+			}
+
+			if !tc.allowed && !errors.Is(err, ErrForbidden) {
+				t.Fatalf("Authorize(%q, %s): got %v want %v", tc.role, tc.permission, err, ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	if role, err := ParseRole(""); err != nil || role != RoleCustomer {
+		t.Errorf("ParseRole(\"\"): got %s, %v want %s", role, err, RoleCustomer)
+	}
+
+	if _, err := ParseRole("root"); err == nil {
+		t.Error("ParseRole(\"root\"): got nil error")
+	}
+}
@@ -0,0 +1,44 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// MinInterval is the shortest time allowed between two runs of a schedule.
+const MinInterval = time.Minute
+
+// ErrTooFrequent is returned when a schedule runs more often than MinInterval.
+var ErrTooFrequent = errors.New("schedule runs too frequently")
+
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Parse parses a standard five field cron expression such as "0 9 1 * *",
+// a descriptor such as "@monthly", or an interval rule such as "@every 720h".
+// Times are evaluated in UTC unless the rule is prefixed with CRON_TZ.
+func Parse(rule string) (cron.Schedule, error) {
+	s, err := parser.Parse(rule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", rule, err)
+	}
+
+	first := s.Next(time.Now().UTC())
+	if s.Next(first).Sub(first) < MinInterval {
+		return nil, fmt.Errorf("%w: %q", ErrTooFrequent, rule)
+	}
+
+	return s, nil
+}
+
+// Next returns the first run of rule strictly after the given time.
+func Next(rule string, after time.Time) (time.Time, error) {
+	s, err := Parse(rule)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return s.Next(after.UTC()), nil
+}
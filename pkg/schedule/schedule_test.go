@@ -0,0 +1,43 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	after := time.Date(2026, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	tcs := []struct {
+		desc string
+		rule string
+		want time.Time
+	}{
+		{desc: "first of each month", rule: "0 9 1 * *", want: time.Date(2026, time.February, 1, 9, 0, 0, 0, time.UTC)},
+		{desc: "monthly descriptor", rule: "@monthly", want: time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{desc: "interval", rule: "@every 24h", want: after.Add(24 * time.Hour)},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Next(tc.rule, after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("Next(%q): got %v want %v", tc.rule, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse("not a schedule"); err == nil {
+		t.Error("expected an error for an invalid rule")
+	}
+
+	if _, err := Parse("@every 10s"); !errors.Is(err, ErrTooFrequent) {
+		t.Errorf("got %v want %v", err, ErrTooFrequent)
+	}
+}
@@ -0,0 +1,129 @@
+package statement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+type camtDocument struct {
+	XMLName xml.Name      `xml:"Document"`
+	Xmlns   string        `xml:"xmlns,attr"`
+	Stmt    camtBkToCstmr `xml:"BkToCstmrStmt"`
+}
+
+type camtBkToCstmr struct {
+	MsgID     string        `xml:"GrpHdr>MsgId"`
+	CreDtTm   string        `xml:"GrpHdr>CreDtTm"`
+	Statement camtStatement `xml:"Stmt"`
+}
+
+type camtStatement struct {
+	ID       string      `xml:"Id"`
+	CreDtTm  string      `xml:"CreDtTm"`
+	FrDtTm   string      `xml:"FrToDt>FrDtTm"`
+	ToDtTm   string      `xml:"FrToDt>ToDtTm"`
+	AcctID   string      `xml:"Acct>Id>Othr>Id"`
+	Ccy      string      `xml:"Acct>Ccy"`
+	Owner    string      `xml:"Acct>Ownr>Nm"`
+	Balances []camtBal   `xml:"Bal"`
+	Entries  []camtEntry `xml:"Ntry"`
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtBal struct {
+	Type      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	Dt        string     `xml:"Dt>DtTm"`
+}
+
+type camtEntry struct {
+	NtryRef    string     `xml:"NtryRef"`
+	Amt        camtAmount `xml:"Amt"`
+	CdtDbtInd  string     `xml:"CdtDbtInd"`
+	Sts        string     `xml:"Sts"`
+	BookgDt    string     `xml:"BookgDt>DtTm"`
+	ValDt      string     `xml:"ValDt>DtTm"`
+	BkTxCd     string     `xml:"BkTxCd>Prtry>Cd"`
+	EndToEndID string     `xml:"NtryDtls>TxDtls>Refs>EndToEndId"`
+	AddtlInf   string     `xml:"AddtlNtryInf"`
+}
+
+func camtTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+// creditDebit returns the ISO 20022 credit debit indicator of amount.
+func creditDebit(amount int64) string {
+	if amount < 0 {
+		return "DBIT"
+	}
+
+	return "CRDT"
+}
+
+func (s Statement) camtAmount(amount int64) camtAmount {
+	return camtAmount{Ccy: s.Currency, Value: formatAmount(abs(amount), s.Exponent)}
+}
+
+func renderCamt053(w io.Writer, s Statement) error {
+	id := fmt.Sprintf("%d-%s-%s", s.AccountID, s.From.UTC().Format("20060102"), s.To.UTC().Format("20060102"))
+	doc := camtDocument{
+		Xmlns: camt053Namespace,
+		Stmt: camtBkToCstmr{
+			MsgID:   fmt.Sprintf("%s-%d", id, s.GeneratedAt.Unix()),
+			CreDtTm: camtTime(s.GeneratedAt),
+			Statement: camtStatement{
+				ID:      id,
+				CreDtTm: camtTime(s.GeneratedAt),
+				FrDtTm:  camtTime(s.From),
+				ToDtTm:  camtTime(s.To),
+				AcctID:  strconv.FormatInt(s.AccountID, 10),
+				Ccy:     s.Currency,
+				Owner:   s.Owner,
+				Balances: []camtBal{
+					{Type: "OPBD", Amt: s.camtAmount(s.OpeningBalance), CdtDbtInd: creditDebit(s.OpeningBalance), Dt: camtTime(s.From)},
+					{Type: "CLBD", Amt: s.camtAmount(s.ClosingBalance), CdtDbtInd: creditDebit(s.ClosingBalance), Dt: camtTime(s.To)},
+				},
+			},
+		},
+	}
+
+	for _, l := range s.Lines {
+		entry := camtEntry{
+			NtryRef:   strconv.FormatInt(l.EntryID, 10),
+			Amt:       s.camtAmount(l.Amount),
+			CdtDbtInd: creditDebit(l.Amount),
+			Sts:       "BOOK",
+			BookgDt:   camtTime(l.BookedAt),
+			ValDt:     camtTime(l.BookedAt),
+			BkTxCd:    "TRANSFER",
+			AddtlInf:  l.description(),
+		}
+
+		if l.TransferID != 0 {
+			entry.EndToEndID = strconv.FormatInt(l.TransferID, 10)
+		} else {
+			entry.EndToEndID = "NOTPROVIDED"
+		}
+
+		doc.Stmt.Statement.Entries = append(doc.Stmt.Statement.Entries, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(doc)
+}
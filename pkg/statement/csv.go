@@ -0,0 +1,46 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{"booked_at", "entry_id", "transfer_id", "counterparty_account_id", "description", "amount", "balance", "currency"}
+
+func renderCSV(w io.Writer, s Statement) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, l := range s.Lines {
+		record := []string{
+			l.BookedAt.UTC().Format(time.RFC3339),
+			strconv.FormatInt(l.EntryID, 10),
+			optionalID(l.TransferID),
+			optionalID(l.CounterpartyAccountID),
+			l.description(),
+			formatAmount(l.Amount, s.Exponent),
+			formatAmount(l.Balance, s.Exponent),
+			s.Currency,
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// optionalID leaves the column empty for a missing id.
+func optionalID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,108 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	ofxHeader     = `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+	ofxTimeLayout = "20060102150405"
+	ofxBankID     = "SIMPLEBANK"
+)
+
+type ofxDocument struct {
+	XMLName xml.Name  `xml:"OFX"`
+	SignOn  ofxSignOn `xml:"SIGNONMSGSRSV1>SONRS"`
+	Bank    ofxStmtRs `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignOn struct {
+	Status   ofxStatus `xml:"STATUS"`
+	DTServer string    `xml:"DTSERVER"`
+	Language string    `xml:"LANGUAGE"`
+}
+
+type ofxStmtRs struct {
+	TrnUID string       `xml:"TRNUID"`
+	Status ofxStatus    `xml:"STATUS"`
+	Stmt   ofxStatement `xml:"STMTRS"`
+}
+
+type ofxStatement struct {
+	CurDef       string       `xml:"CURDEF"`
+	BankID       string       `xml:"BANKACCTFROM>BANKID"`
+	AcctID       string       `xml:"BANKACCTFROM>ACCTID"`
+	AcctType     string       `xml:"BANKACCTFROM>ACCTTYPE"`
+	DTStart      string       `xml:"BANKTRANLIST>DTSTART"`
+	DTEnd        string       `xml:"BANKTRANLIST>DTEND"`
+	Transactions []ofxStmtTrn `xml:"BANKTRANLIST>STMTTRN"`
+	LedgerBal    ofxLedgerBal `xml:"LEDGERBAL"`
+}
+
+type ofxStmtTrn struct {
+	TrnType  string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	TrnAmt   string `xml:"TRNAMT"`
+	FitID    string `xml:"FITID"`
+	Name     string `xml:"NAME"`
+}
+
+type ofxLedgerBal struct {
+	BalAmt string `xml:"BALAMT"`
+	DTAsOf string `xml:"DTASOF"`
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format(ofxTimeLayout)
+}
+
+func renderOFX(w io.Writer, s Statement) error {
+	ok := ofxStatus{Code: 0, Severity: "INFO"}
+	doc := ofxDocument{
+		SignOn: ofxSignOn{Status: ok, DTServer: ofxTime(s.GeneratedAt), Language: "ENG"},
+		Bank: ofxStmtRs{
+			TrnUID: "0",
+			Status: ok,
+			Stmt: ofxStatement{
+				CurDef:    s.Currency,
+				BankID:    ofxBankID,
+				AcctID:    strconv.FormatInt(s.AccountID, 10),
+				AcctType:  "CHECKING",
+				DTStart:   ofxTime(s.From),
+				DTEnd:     ofxTime(s.To),
+				LedgerBal: ofxLedgerBal{BalAmt: formatAmount(s.ClosingBalance, s.Exponent), DTAsOf: ofxTime(s.To)},
+			},
+		},
+	}
+
+	for _, l := range s.Lines {
+		trnType := "CREDIT"
+		if l.Amount < 0 {
+			trnType = "DEBIT"
+		}
+
+		doc.Bank.Stmt.Transactions = append(doc.Bank.Stmt.Transactions, ofxStmtTrn{
+			TrnType:  trnType,
+			DTPosted: ofxTime(l.BookedAt),
+			TrnAmt:   formatAmount(l.Amount, s.Exponent),
+			FitID:    strconv.FormatInt(l.EntryID, 10),
+			Name:     l.description(),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header+ofxHeader); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(doc)
+}
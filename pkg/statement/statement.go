@@ -0,0 +1,132 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a statement export format.
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatOFX     Format = "ofx"
+	FormatCamt053 Format = "camt053"
+)
+
+// Statement stores the entries of an account for a period and the balances around them.
+// Amounts are in minor units of the account currency.
+type Statement struct {
+	AccountID      int64
+	Owner          string
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance int64
+	ClosingBalance int64
+	GeneratedAt    time.Time
+	Lines          []Line
+	// Exponent is the number of decimals of the minor unit of the currency.
+	Exponent int
+}
+
+// Line is an entry of the statement.
+type Line struct {
+	EntryID    int64
+	TransferID int64
+	// CounterpartyAccountID is 0 when the entry was not posted by a transfer.
+	CounterpartyAccountID int64
+	Amount                int64
+	// Balance is the balance of the account after the entry.
+	Balance  int64
+	BookedAt time.Time
+}
+
+// ParseFormat returns the Format of name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatCSV, FormatOFX, FormatCamt053:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("unsupported statement format %q", name)
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatOFX:
+		return "application/x-ofx"
+	default:
+		return "application/xml"
+	}
+}
+
+// Filename returns the file name of the statement exported as f.
+func (f Format) Filename(s Statement) string {
+	ext := string(f)
+	if f == FormatCamt053 {
+		ext = "xml"
+	}
+
+	return fmt.Sprintf("statement-%d-%s-%s.%s", s.AccountID, s.From.Format("20060102"), s.To.Format("20060102"), ext)
+}
+
+// Render writes the statement to w in the format f.
+func Render(w io.Writer, f Format, s Statement) error {
+	switch f {
+	case FormatCSV:
+		return renderCSV(w, s)
+	case FormatOFX:
+		return renderOFX(w, s)
+	case FormatCamt053:
+		return renderCamt053(w, s)
+	}
+
+	return fmt.Errorf("unsupported statement format %q", f)
+}
+
+// description is a human readable description of the line.
+func (l Line) description() string {
+	switch {
+	case l.CounterpartyAccountID == 0:
+		return "Adjustment"
+	case l.Amount < 0:
+		return fmt.Sprintf("Transfer to account %d", l.CounterpartyAccountID)
+	default:
+		return fmt.Sprintf("Transfer from account %d", l.CounterpartyAccountID)
+	}
+}
+
+// formatAmount formats an amount in minor units as a decimal string with the decimals of the exponent,
+// such as -1234 with an exponent of 2 as "-12.34".
+func formatAmount(amount int64, decimals int) string {
+	if decimals == 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+
+	sign := ""
+	abs := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		abs = uint64(-amount)
+	}
+
+	digits := fmt.Sprintf("%0*d", decimals+1, abs)
+	cut := len(digits) - decimals
+	return sign + digits[:cut] + "." + digits[cut:]
+}
+
+// abs returns the absolute value of amount.
+func abs(amount int64) int64 {
+	if amount < 0 {
+		return -amount
+	}
+
+	return amount
+}
@@ -0,0 +1,73 @@
+package statement
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tcs := []struct {
+		amount   int64
+		exponent int
+		want     string
+	}{
+		{amount: 1234, exponent: 2, want: "12.34"},
+		{amount: -1234, exponent: 2, want: "-12.34"},
+		{amount: 5, exponent: 2, want: "0.05"},
+		{amount: -5, exponent: 2, want: "-0.05"},
+		{amount: 0, exponent: 2, want: "0.00"},
+		{amount: 1234, exponent: 0, want: "1234"},
+		{amount: 12345, exponent: 3, want: "12.345"},
+		{amount: -7, exponent: 3, want: "-0.007"},
+	}
+
+	for _, tc := range tcs {
+		if got := formatAmount(tc.amount, tc.exponent); got != tc.want {
+			t.Errorf("formatAmount(%d, %d): got %s want %s", tc.amount, tc.exponent, got, tc.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	from := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	s := Statement{
+		AccountID:      7,
+		Owner:          "orlando",
+		Currency:       "USD",
+		Exponent:       2,
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: 1000,
+		ClosingBalance: 750,
+		GeneratedAt:    from.AddDate(0, 1, 1),
+		Lines: []Line{
+			{EntryID: 1, TransferID: 10, CounterpartyAccountID: 8, Amount: -250, Balance: 750, BookedAt: from.Add(time.Hour)},
+		},
+	}
+
+	tcs := []struct {
+		format Format
+		want   []string
+	}{
+		{format: FormatCSV, want: []string{"2026-09-01T01:00:00Z,1,10,8,Transfer to account 8,-2.50,7.50,USD"}},
+		{format: FormatOFX, want: []string{`<?OFX OFXHEADER="200"`, "<TRNTYPE>DEBIT</TRNTYPE>", "<TRNAMT>-2.50</TRNAMT>", "<BALAMT>7.50</BALAMT>"}},
+		{format: FormatCamt053, want: []string{camt053Namespace, "<Cd>OPBD</Cd>", `<Amt Ccy="USD">2.50</Amt>`, "<CdtDbtInd>DBIT</CdtDbtInd>", "<EndToEndId>10</EndToEndId>"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(string(tc.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tc.format, s); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, want := range tc.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("%s output does not contain %q:\n%s", tc.format, want, buf.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// EdDSAMaker signs JWTs with an Ed25519 private key, any service with the public key can verify them.
+type EdDSAMaker struct {
+	privateKey ed25519.PrivateKey
+	jwk        JWK
+}
+
+// NewEdDSAMaker returns an implementation of the Maker interface by providing the base64 encoded seed of
+// the Ed25519 private key.
+func NewEdDSAMaker(privateKey string) (Maker, error) {
+	seed, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key encoding: %w", err)
+	}
+
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid private key size: must have %v", ed25519.SeedSize)
+	}
+
+	key := ed25519.NewKeyFromSeed(seed)
+	return &EdDSAMaker{
+		privateKey: key,
+		jwk:        NewJWK(key.Public().(ed25519.PublicKey)),
+	}, nil
+}
+
+// CreateToken creates a JWT signed with EdDSA, its kid header identifies the key in the JWKS.
+func (e *EdDSAMaker) CreateToken(username, role string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, payload)
+	token.Header["kid"] = e.jwk.Kid
+	signed, err := token.SignedString(e.privateKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, payload, nil
+}
+
+// VerfifyToken verifies the JWT with the public key.
+func (e *EdDSAMaker) VerfifyToken(token string) (*Payload, error) {
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodEd25519); !ok {
+			return nil, ErrInvalidToken
+		}
+
+		if kid, _ := t.Header["kid"].(string); kid != e.jwk.Kid {
+			return nil, ErrInvalidToken
+		}
+
+		return e.privateKey.Public(), nil
+	}
+
+	return parseJWT(token, keyFunc)
+}
+
+// JWKS returns the public key of the maker.
+func (e *EdDSAMaker) JWKS() JWKS {
+	return JWKS{Keys: []JWK{e.jwk}}
+}
@@ -0,0 +1,108 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestEdDSAMaker(t *testing.T, seed byte) Maker {
+	t.Helper()
+
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(seed), ed25519.SeedSize)))
+	maker, err := NewEdDSAMaker(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return maker
+}
+
+func TestEdDSAMaker(t *testing.T) {
+	maker := newTestEdDSAMaker(t, 'a')
+
+	token, created, err := maker.CreateToken("orlandorode97", "customer", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := maker.VerfifyToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.ID != created.ID || payload.Username != created.Username {
+		t.Errorf("VerfifyToken: got %+v want %+v", payload, created)
+	}
+
+	if _, err := newTestEdDSAMaker(t, 'b').VerfifyToken(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("VerfifyToken with another key: got %v want %v", err, ErrInvalidToken)
+	}
+
+	expired, _, err := maker.CreateToken("orlandorode97", "customer", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := maker.VerfifyToken(expired); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("VerfifyToken expired token: got %v want %v", err, ErrExpiredToken)
+	}
+
+	// A HMAC token signed with the public key must not be accepted.
+	jwk := maker.(KeySet).JWKS().Keys[0]
+	publicKey, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hmac, err := NewJWTMaker(string(publicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forged, _, err := hmac.CreateToken("orlandorode97", "customer", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := maker.VerfifyToken(forged); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("VerfifyToken HMAC token: got %v want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestNewEdDSAMaker(t *testing.T) {
+	tcs := []struct {
+		desc string
+		key  string
+	}{
+		{desc: "not base64", key: "not base64!"},
+		{desc: "short seed", key: base64.StdEncoding.EncodeToString([]byte("short"))},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := NewEdDSAMaker(tc.key); err == nil {
+				t.Errorf("NewEdDSAMaker(%q): got nil error", tc.key)
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	jwks := newTestEdDSAMaker(t, 'a').(KeySet).JWKS()
+	if len(jwks.Keys) != 1 {
+		t.Fatalf("JWKS keys: got %d want 1", len(jwks.Keys))
+	}
+
+	jwk := jwks.Keys[0]
+	if jwk.Kty != "OKP" || jwk.Crv != "Ed25519" || jwk.Alg != "EdDSA" || jwk.Kid == "" {
+		t.Errorf("JWKS key: got %+v", jwk)
+	}
+
+	if again := newTestEdDSAMaker(t, 'a').(KeySet).JWKS().Keys[0].Kid; again != jwk.Kid {
+		t.Errorf("JWKS kid is not stable: got %s want %s", again, jwk.Kid)
+	}
+}
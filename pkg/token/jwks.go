@@ -0,0 +1,50 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// JWK is the public key of an Ed25519 signing key as published in a JWKS, see RFC 8037.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+// JWKS is the set of public keys other services verify the tokens with.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySet is implemented by the makers that sign tokens with a private key, so their public keys can be published.
+type KeySet interface {
+	JWKS() JWKS
+}
+
+// NewJWK returns the JWK of the public key, its key ID is the RFC 7638 thumbprint of the key.
+func NewJWK(publicKey ed25519.PublicKey) JWK {
+	x := base64.RawURLEncoding.EncodeToString(publicKey)
+
+	// The thumbprint hashes the required members of the key in lexicographic order.
+	thumbprint, _ := json.Marshal(struct {
+		Crv string `json:"crv"`
+		Kty string `json:"kty"`
+		X   string `json:"x"`
+	}{Crv: "Ed25519", Kty: "OKP", X: x})
+	sum := sha256.Sum256(thumbprint)
+
+	return JWK{
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   x,
+		Kid: base64.RawURLEncoding.EncodeToString(sum[:]),
+		Use: "sig",
+		Alg: "EdDSA",
+	}
+}
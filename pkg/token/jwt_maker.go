@@ -13,29 +13,44 @@ const minSecretSize = 12
 // JWTMaker structs stores the secret key to sign the JWT.
 type JWTMaker struct {
 	secretKey string
+	// keyID is set in the kid header of the tokens when the key belongs to a keyring.
+	keyID string
 }
 
 // NewJWTMaker returns an implementation of the Maker interface by providing the secretKey to sign the JWT
 func NewJWTMaker(secretKey string) (Maker, error) {
+	return newJWTMaker("", secretKey)
+}
+
+func newJWTMaker(keyID, secretKey string) (*JWTMaker, error) {
 	if len(secretKey) < minSecretSize {
 		return nil, fmt.Errorf("invalid secret key size: must be at least %v", minSecretSize)
 	}
 
 	return &JWTMaker{
 		secretKey: secretKey,
+		keyID:     keyID,
 	}, nil
 }
 
 // CreateToken creates a jwt token.
-func (j *JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration) // creates a new payload.
+func (j *JWTMaker) CreateToken(username, role string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration) // creates a new payload.
 	if err != nil {
 		return "", nil, err
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
-	signed, err := token.SignedString(j.secretKey)
-	return signed, payload, err
+	if j.keyID != "" {
+		token.Header["kid"] = j.keyID
+	}
+
+	signed, err := token.SignedString([]byte(j.secretKey))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, payload, nil
 }
 
 // VerfifyToken verifies the JWT.
@@ -45,9 +60,18 @@ func (j *JWTMaker) VerfifyToken(token string) (*Payload, error) {
 			return nil, ErrInvalidToken
 		}
 
+		if kid, _ := t.Header["kid"].(string); j.keyID != "" && kid != j.keyID {
+			return nil, ErrInvalidToken
+		}
+
 		return []byte(j.secretKey), nil
 	}
 
+	return parseJWT(token, keyFunc)
+}
+
+// parseJWT parses the JWT with the key returned by keyFunc, its claims are validated as a Payload.
+func parseJWT(token string, keyFunc jwt.Keyfunc) (*Payload, error) {
 	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
 	if err != nil {
 		vErrr, ok := err.(*jwt.ValidationError)
@@ -55,12 +79,13 @@ func (j *JWTMaker) VerfifyToken(token string) (*Payload, error) {
 			return nil, ErrExpiredToken
 		}
 
-		return nil, err
+		return nil, ErrInvalidToken
 	}
 
 	payload, ok := jwtToken.Claims.(*Payload)
-	if !ok && payload.Valid() != nil {
+	if !ok {
 		return nil, ErrInvalidToken
 	}
-	return nil, nil
+
+	return payload, nil
 }
@@ -1,7 +1,70 @@
 package token
 
-import "testing"
+import (
+	"errors"
+	"testing"
+	"time"
+)
 
-func TestCreateToken(t *testing.T) {}
+func TestCreateToken(t *testing.T) {
+	maker, err := NewJWTMaker("a-secret-key-of-32-characters-ok")
+	if err != nil {
+		t.Fatal(err)
+	}
 
-func TestVerifyToken(t *testing.T) {}
+	token, payload, err := maker.CreateToken("orlandorode97", "customer", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token == "" {
+		t.Error("CreateToken: got empty token")
+	}
+
+	if payload.Username != "orlandorode97" {
+		t.Errorf("CreateToken username: got %s want orlandorode97", payload.Username)
+	}
+
+	if payload.Role != "customer" {
+		t.Errorf("CreateToken role: got %s want customer", payload.Role)
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	maker, err := NewJWTMaker("a-secret-key-of-32-characters-ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := NewJWTMaker("another-secret-key-of-32-chars!!")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, created, err := maker.CreateToken("orlandorode97", "customer", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := maker.VerfifyToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.ID != created.ID || payload.Username != created.Username {
+		t.Errorf("VerfifyToken: got %+v want %+v", payload, created)
+	}
+
+	if _, err := other.VerfifyToken(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("VerfifyToken with another key: got %v want %v", err, ErrInvalidToken)
+	}
+
+	expired, _, err := maker.CreateToken("orlandorode97", "customer", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := maker.VerfifyToken(expired); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("VerfifyToken expired token: got %v want %v", err, ErrExpiredToken)
+	}
+}
@@ -0,0 +1,192 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// keySize is the size of the generated keys, it is the key size of PASETO v2.local and the seed size of Ed25519.
+const keySize = 32
+
+// Key is a signing key of a keyring.
+type Key struct {
+	ID string `json:"id"`
+	// Secret is the base64 encoded symmetric key of the paseto and jwt makers or the Ed25519 seed of the eddsa maker.
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+	// RetiredAt is when the key stopped being the primary key, its tokens are accepted until the grace period ends.
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+}
+
+// Keyring stores the signing keys of a maker, new tokens are signed with the primary key and tokens signed
+// with any other key are accepted until its grace period ends.
+type Keyring struct {
+	Kind    string `json:"kind"`
+	Primary string `json:"primary"`
+	Keys    []Key  `json:"keys"`
+}
+
+// NewKeyring returns a keyring of the kind with a new primary key.
+func NewKeyring(kind string, now time.Time) (*Keyring, error) {
+	key, err := GenerateKey(kind, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keyring{
+		Kind:    kind,
+		Primary: key.ID,
+		Keys:    []Key{key},
+	}, nil
+}
+
+// GenerateKey generates a random key of the kind, the ID of an Ed25519 key is the thumbprint of its public key
+// so it is the kid published in the JWKS.
+func GenerateKey(kind string, now time.Time) (Key, error) {
+	secret := make([]byte, keySize)
+	if _, err := rand.Read(secret); err != nil {
+		return Key{}, err
+	}
+
+	key := Key{
+		Secret:    base64.StdEncoding.EncodeToString(secret),
+		CreatedAt: now.UTC(),
+	}
+
+	switch kind {
+	case KindPaseto, KindJWT:
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			return Key{}, err
+		}
+		key.ID = hex.EncodeToString(id)
+	case KindEdDSA:
+		publicKey := ed25519.NewKeyFromSeed(secret).Public().(ed25519.PublicKey)
+		key.ID = NewJWK(publicKey).Kid
+	default:
+		return Key{}, fmt.Errorf("unknown token maker %q: must be %s, %s or %s", kind, KindPaseto, KindJWT, KindEdDSA)
+	}
+
+	return key, nil
+}
+
+// ParseKeyring parses a JSON keyring and valids its primary key is one of its active keys.
+func ParseKeyring(data []byte) (*Keyring, error) {
+	var keyring Keyring
+	if err := json.Unmarshal(data, &keyring); err != nil {
+		return nil, fmt.Errorf("invalid keyring: %w", err)
+	}
+
+	if keyring.Kind == "" {
+		keyring.Kind = KindPaseto
+	}
+
+	ids := make(map[string]bool, len(keyring.Keys))
+	for _, key := range keyring.Keys {
+		if key.ID == "" {
+			return nil, errors.New("invalid keyring: every key must have an id")
+		}
+
+		if ids[key.ID] {
+			return nil, fmt.Errorf("invalid keyring: duplicated key %s", key.ID)
+		}
+		ids[key.ID] = true
+
+		if key.ID == keyring.Primary && key.RetiredAt != nil {
+			return nil, fmt.Errorf("invalid keyring: primary key %s is retired", key.ID)
+		}
+	}
+
+	if !ids[keyring.Primary] {
+		return nil, fmt.Errorf("invalid keyring: primary key %q not found", keyring.Primary)
+	}
+
+	return &keyring, nil
+}
+
+// LoadKeyring reads the keyring from a key file.
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseKeyring(data)
+}
+
+// Save writes the keyring to the key file, readable by its owner only. The file is replaced at once so
+// the servers never read a partial keyring.
+func (k *Keyring) Save(path string) error {
+	data, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Rotate generates a new primary key and retires the previous one, keys whose grace period ended are removed.
+func (k *Keyring) Rotate(now time.Time, gracePeriod time.Duration) (Key, error) {
+	key, err := GenerateKey(k.Kind, now)
+	if err != nil {
+		return key, err
+	}
+
+	retiredAt := now.UTC()
+	for i := range k.Keys {
+		if k.Keys[i].ID == k.Primary {
+			k.Keys[i].RetiredAt = &retiredAt
+		}
+	}
+
+	k.Keys = append(k.Keys, key)
+	k.Primary = key.ID
+	k.Prune(now, gracePeriod)
+	return key, nil
+}
+
+// Prune removes the retired keys whose grace period ended and returns them.
+func (k *Keyring) Prune(now time.Time, gracePeriod time.Duration) []Key {
+	var (
+		active []Key
+		pruned []Key
+	)
+	for _, key := range k.Keys {
+		if key.expired(now, gracePeriod) {
+			pruned = append(pruned, key)
+			continue
+		}
+		active = append(active, key)
+	}
+
+	k.Keys = active
+	return pruned
+}
+
+// expired reports whether the grace period of a retired key ended.
+func (k Key) expired(now time.Time, gracePeriod time.Duration) bool {
+	return k.RetiredAt != nil && !now.Before(k.RetiredAt.Add(gracePeriod))
+}
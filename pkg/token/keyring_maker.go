@@ -0,0 +1,227 @@
+package token
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/o1egl/paseto"
+)
+
+// keyringCheckInterval is how often the key file is checked for changes.
+const keyringCheckInterval = time.Minute
+
+// KeyringMaker signs new tokens with the primary key of a keyring and verifies them with the key named by their
+// key ID, the kid header of JWTs or the footer of PASETO tokens.
+type KeyringMaker struct {
+	// path is the key file of the keyring, it is empty when the keyring was given as JSON.
+	path        string
+	gracePeriod time.Duration
+
+	mu        sync.RWMutex
+	keys      *keyringKeys
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// keyringKeys are the makers of the keys of a keyring.
+type keyringKeys struct {
+	kind    string
+	primary Maker
+	ids     []string
+	keys    map[string]keyringKey
+}
+
+type keyringKey struct {
+	maker Maker
+	key   Key
+}
+
+// NewKeyringMaker returns an implementation of the Maker interface by providing the keyring, tokens signed
+// with a retired key are accepted during the grace period.
+func NewKeyringMaker(keyring *Keyring, gracePeriod time.Duration) (Maker, error) {
+	keys, err := newKeyringKeys(keyring)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyringMaker{
+		gracePeriod: gracePeriod,
+		keys:        keys,
+	}, nil
+}
+
+// NewKeyringFileMaker returns a KeyringMaker of the keyring stored in the key file. The file is checked every minute
+// and reloaded when it changes, so the keys are rotated without restarting the servers.
+func NewKeyringFileMaker(path string, gracePeriod time.Duration) (Maker, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := LoadKeyring(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := newKeyringKeys(keyring)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyringMaker{
+		path:        path,
+		gracePeriod: gracePeriod,
+		keys:        keys,
+		modTime:     info.ModTime(),
+		checkedAt:   time.Now(),
+	}, nil
+}
+
+func newKeyringKeys(keyring *Keyring) (*keyringKeys, error) {
+	keys := &keyringKeys{
+		kind: keyring.Kind,
+		ids:  make([]string, 0, len(keyring.Keys)),
+		keys: make(map[string]keyringKey, len(keyring.Keys)),
+	}
+
+	for _, key := range keyring.Keys {
+		secret, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret of key %s: %w", key.ID, err)
+		}
+
+		var maker Maker
+		switch keyring.Kind {
+		case KindPaseto:
+			maker, err = newPasetoMaker(key.ID, string(secret))
+		case KindJWT:
+			maker, err = newJWTMaker(key.ID, string(secret))
+		case KindEdDSA:
+			maker, err = NewEdDSAMaker(key.Secret)
+			if err == nil && maker.(*EdDSAMaker).jwk.Kid != key.ID {
+				err = fmt.Errorf("id must be the thumbprint %s of the public key", maker.(*EdDSAMaker).jwk.Kid)
+			}
+		default:
+			err = fmt.Errorf("unknown token maker %q: must be %s, %s or %s", keyring.Kind, KindPaseto, KindJWT, KindEdDSA)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", key.ID, err)
+		}
+
+		keys.ids = append(keys.ids, key.ID)
+		keys.keys[key.ID] = keyringKey{maker: maker, key: key}
+		if key.ID == keyring.Primary {
+			keys.primary = maker
+		}
+	}
+
+	if keys.primary == nil {
+		return nil, fmt.Errorf("primary key %q not found", keyring.Primary)
+	}
+
+	return keys, nil
+}
+
+// current returns the keys of the keyring, reloading the key file when it changed. The keys loaded before are
+// kept when the key file can't be read or is invalid.
+func (k *KeyringMaker) current() *keyringKeys {
+	k.mu.RLock()
+	if k.path == "" || time.Since(k.checkedAt) < keyringCheckInterval {
+		defer k.mu.RUnlock()
+		return k.keys
+	}
+	k.mu.RUnlock()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if time.Since(k.checkedAt) < keyringCheckInterval {
+		return k.keys
+	}
+	k.checkedAt = time.Now()
+
+	info, err := os.Stat(k.path)
+	if err != nil || info.ModTime().Equal(k.modTime) {
+		return k.keys
+	}
+
+	keyring, err := LoadKeyring(k.path)
+	if err != nil {
+		return k.keys
+	}
+
+	keys, err := newKeyringKeys(keyring)
+	if err != nil {
+		return k.keys
+	}
+
+	k.keys, k.modTime = keys, info.ModTime()
+	return k.keys
+}
+
+// CreateToken creates a token signed with the primary key.
+func (k *KeyringMaker) CreateToken(username, role string, duration time.Duration) (string, *Payload, error) {
+	return k.current().primary.CreateToken(username, role, duration)
+}
+
+// VerfifyToken verifies the token with the key named by its key ID, the key must be active or retired
+// within the grace period.
+func (k *KeyringMaker) VerfifyToken(token string) (*Payload, error) {
+	keys := k.current()
+
+	keyID, err := tokenKeyID(keys.kind, token)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	key, ok := keys.keys[keyID]
+	if !ok || key.key.expired(time.Now(), k.gracePeriod) {
+		return nil, ErrInvalidToken
+	}
+
+	return key.maker.VerfifyToken(token)
+}
+
+// JWKS returns the public keys of the keyring that are active or retired within the grace period,
+// keyrings of symmetric keys have no public keys.
+func (k *KeyringMaker) JWKS() JWKS {
+	keys := k.current()
+
+	jwks := JWKS{Keys: []JWK{}}
+	now := time.Now()
+	for _, id := range keys.ids {
+		key := keys.keys[id]
+		eddsa, ok := key.maker.(*EdDSAMaker)
+		if !ok || key.key.expired(now, k.gracePeriod) {
+			continue
+		}
+
+		jwks.Keys = append(jwks.Keys, eddsa.jwk)
+	}
+
+	return jwks
+}
+
+// tokenKeyID returns the key ID of the token without verifying it.
+func tokenKeyID(kind, token string) (string, error) {
+	if kind == KindPaseto {
+		var footer pasetoFooter
+		if err := paseto.ParseFooter(token, &footer); err != nil {
+			return "", err
+		}
+
+		return footer.Kid, nil
+	}
+
+	jwtToken, _, err := jwt.NewParser().ParseUnverified(token, &Payload{})
+	if err != nil {
+		return "", err
+	}
+
+	kid, _ := jwtToken.Header["kid"].(string)
+	return kid, nil
+}
@@ -0,0 +1,227 @@
+package token
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestKeyringMakerRotation(t *testing.T) {
+	for _, kind := range []string{KindPaseto, KindJWT, KindEdDSA} {
+		t.Run(kind, func(t *testing.T) {
+			keyring, err := NewKeyring(kind, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			maker, err := NewKeyringMaker(keyring, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			oldToken, created, err := maker.CreateToken("orlandorode97", "customer", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if keyID, err := tokenKeyID(kind, oldToken); err != nil || keyID != keyring.Primary {
+				t.Errorf("token key id: got %q, %v want %s", keyID, err, keyring.Primary)
+			}
+
+			oldKey := keyring.Primary
+			newKey, err := keyring.Rotate(time.Now(), time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if keyring.Primary != newKey.ID || len(keyring.Keys) != 2 {
+				t.Fatalf("Rotate: got primary %s and %d keys want %s and 2", keyring.Primary, len(keyring.Keys), newKey.ID)
+			}
+
+			maker, err = NewKeyringMaker(keyring, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			payload, err := maker.VerfifyToken(oldToken)
+			if err != nil {
+				t.Fatalf("VerfifyToken with retired key: %v", err)
+			}
+
+			if payload.ID != created.ID {
+				t.Errorf("VerfifyToken: got %+v want %+v", payload, created)
+			}
+
+			newToken, _, err := maker.CreateToken("orlandorode97", "customer", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if keyID, _ := tokenKeyID(kind, newToken); keyID != newKey.ID {
+				t.Errorf("token key id after rotation: got %q want %s", keyID, newKey.ID)
+			}
+
+			// Once the grace period ends the tokens of the retired key are rejected.
+			retiredAt := time.Now().Add(-2 * time.Hour)
+			for i := range keyring.Keys {
+				if keyring.Keys[i].ID == oldKey {
+					keyring.Keys[i].RetiredAt = &retiredAt
+				}
+			}
+
+			maker, err = NewKeyringMaker(keyring, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := maker.VerfifyToken(oldToken); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("VerfifyToken after grace period: got %v want %v", err, ErrInvalidToken)
+			}
+
+			if _, err := maker.VerfifyToken(newToken); err != nil {
+				t.Errorf("VerfifyToken with primary key: %v", err)
+			}
+
+			if pruned := keyring.Prune(time.Now(), time.Hour); len(pruned) != 1 || pruned[0].ID != oldKey {
+				t.Errorf("Prune: got %+v want key %s", pruned, oldKey)
+			}
+		})
+	}
+}
+
+func TestKeyringMakerRejectsOtherKeys(t *testing.T) {
+	newMaker := func() Maker {
+		keyring, err := NewKeyring(KindPaseto, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		maker, err := NewKeyringMaker(keyring, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return maker
+	}
+
+	token, _, err := newMaker().CreateToken("orlandorode97", "customer", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newMaker().VerfifyToken(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("VerfifyToken with another keyring: got %v want %v", err, ErrInvalidToken)
+	}
+
+	// Tokens without a key ID are not accepted by a keyring.
+	single, err := NewPasetoMaker("12345678901234567890123456789012")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, _, err = single.CreateToken("orlandorode97", "customer", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newMaker().VerfifyToken(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("VerfifyToken without key id: got %v want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestKeyringMakerJWKS(t *testing.T) {
+	keyring, err := NewKeyring(KindEdDSA, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := keyring.Rotate(time.Now(), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	maker, err := NewKeyringMaker(keyring, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := maker.(KeySet).JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("JWKS keys: got %d want 2", len(jwks.Keys))
+	}
+
+	for i, jwk := range jwks.Keys {
+		if jwk.Kid != keyring.Keys[i].ID {
+			t.Errorf("JWKS kid: got %s want %s", jwk.Kid, keyring.Keys[i].ID)
+		}
+	}
+}
+
+func TestKeyringFileMakerReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	keyring, err := NewKeyring(KindJWT, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := keyring.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	maker, err := NewMaker(Config{Kind: KindJWT, KeyringFile: path, GracePeriod: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newKey, err := keyring.Rotate(time.Now(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := keyring.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// The key file is checked at most once a minute.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	maker.(*KeyringMaker).checkedAt = time.Now().Add(-keyringCheckInterval)
+
+	token, _, err := maker.CreateToken("orlandorode97", "customer", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keyID, _ := tokenKeyID(KindJWT, token); keyID != newKey.ID {
+		t.Errorf("token key id after reload: got %q want %s", keyID, newKey.ID)
+	}
+}
+
+func TestParseKeyring(t *testing.T) {
+	tcs := []struct {
+		desc string
+		data string
+	}{
+		{desc: "not json", data: "keys"},
+		{desc: "primary not found", data: `{"kind":"jwt","primary":"b","keys":[{"id":"a","secret":"c2VjcmV0"}]}`},
+		{desc: "duplicated key", data: `{"kind":"jwt","primary":"a","keys":[{"id":"a"},{"id":"a"}]}`},
+		{desc: "retired primary", data: `{"kind":"jwt","primary":"a","keys":[{"id":"a","retired_at":"2026-01-01T00:00:00Z"}]}`},
+		{desc: "key without id", data: `{"kind":"jwt","primary":"a","keys":[{"id":"a"},{"secret":"c2VjcmV0"}]}`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := ParseKeyring([]byte(tc.data)); err == nil {
+				t.Errorf("ParseKeyring(%s): got nil error", tc.data)
+			}
+		})
+	}
+
+	if _, err := NewMaker(Config{Kind: KindEdDSA, Keyring: `{"kind":"jwt","primary":"a","keys":[{"id":"a","secret":"c2VjcmV0LWtleS1vZi10aGUta2V5cmluZw=="}]}`}); err == nil {
+		t.Error("NewMaker with a keyring of another kind: got nil error")
+	}
+}
@@ -11,9 +11,20 @@ import (
 type PasetoMaker struct {
 	paseto       *paseto.V2
 	symmetricKey []byte
+	// keyID is set in the footer of the tokens when the key belongs to a keyring.
+	keyID string
+}
+
+// pasetoFooter is the footer of the tokens encrypted with a key of a keyring.
+type pasetoFooter struct {
+	Kid string `json:"kid"`
 }
 
 func NewPasetoMaker(symmetricKey string) (Maker, error) {
+	return newPasetoMaker("", symmetricKey)
+}
+
+func newPasetoMaker(keyID, symmetricKey string) (*PasetoMaker, error) {
 	if len(symmetricKey) != chacha20poly1305.KeySize {
 		return nil, fmt.Errorf("invalid key size: must have %v", chacha20poly1305.KeySize)
 	}
@@ -21,24 +32,40 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	return &PasetoMaker{
 		paseto:       paseto.NewV2(),
 		symmetricKey: []byte(symmetricKey),
+		keyID:        keyID,
 	}, nil
 }
-func (p *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration) // creates a new payload.
+func (p *PasetoMaker) CreateToken(username, role string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration) // creates a new payload.
 	if err != nil {
 		return "", nil, err
 	}
-	encrypted, err := p.paseto.Encrypt(p.symmetricKey, payload, nil)
+
+	var footer interface{}
+	if p.keyID != "" {
+		footer = pasetoFooter{Kid: p.keyID}
+	}
+
+	encrypted, err := p.paseto.Encrypt(p.symmetricKey, payload, footer)
 	return encrypted, payload, err
 }
 
 func (p *PasetoMaker) VerfifyToken(token string) (*Payload, error) {
 	payload := &Payload{}
-	err := p.paseto.Decrypt(token, p.symmetricKey, payload, nil) // Decrypt by providing the token and the symmetricKey
+	var footer interface{}
+	if p.keyID != "" {
+		footer = &pasetoFooter{}
+	}
+
+	err := p.paseto.Decrypt(token, p.symmetricKey, payload, footer) // Decrypt by providing the token and the symmetricKey
 	if err != nil {
 		return nil, ErrInvalidToken
 	}
 
+	if p.keyID != "" && footer.(*pasetoFooter).Kid != p.keyID {
+		return nil, ErrInvalidToken
+	}
+
 	if err = payload.Valid(); err != nil {
 		return nil, err
 	}
@@ -14,14 +14,16 @@ var (
 
 // Payload struct stores the information to be at the JWT payload.
 type Payload struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	// Role is the role of the user when the token was issued, tokens issued before roles existed have none.
+	Role      string    `json:"role"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
-// NewPayload returns a pointer of Payload by providing username and the expire duration.
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+// NewPayload returns a pointer of Payload by providing username, role and the expire duration.
+func NewPayload(username, role string, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -30,6 +32,7 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
+		Role:      role,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
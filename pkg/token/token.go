@@ -1,9 +1,84 @@
 package token
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// KindPaseto encrypts PASETO v2.local tokens with a symmetric key, it is the default.
+	KindPaseto = "paseto"
+	// KindJWT signs JWTs with HMAC SHA-256 and a secret key.
+	KindJWT = "jwt"
+	// KindEdDSA signs JWTs with an Ed25519 private key, its public key is published in the JWKS.
+	KindEdDSA = "eddsa"
+)
 
 // Maker provides the all functions to create and verify any token.
 type Maker interface {
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	CreateToken(username, role string, duration time.Duration) (string, *Payload, error)
 	VerfifyToken(token string) (*Payload, error)
 }
+
+// Config selects the maker and its keys.
+type Config struct {
+	Kind string
+	// SymmetricKey is the key of the paseto and jwt makers, PrivateKey the base64 encoded Ed25519 seed of the eddsa maker.
+	SymmetricKey string
+	PrivateKey   string
+	// KeyringFile is the key file of a keyring, the keys above are ignored when a keyring is set.
+	KeyringFile string
+	// Keyring is a JSON keyring, used when there is no key file.
+	Keyring string
+	// GracePeriod is how long tokens signed with a retired key of the keyring are accepted.
+	GracePeriod time.Duration
+}
+
+// NewMaker returns the maker of the kind, it signs the tokens with the keyring when one is set.
+func NewMaker(conf Config) (Maker, error) {
+	if conf.KeyringFile != "" || conf.Keyring != "" {
+		return newKeyringMaker(conf)
+	}
+
+	switch conf.Kind {
+	case KindPaseto, "":
+		return NewPasetoMaker(conf.SymmetricKey)
+	case KindJWT:
+		return NewJWTMaker(conf.SymmetricKey)
+	case KindEdDSA:
+		return NewEdDSAMaker(conf.PrivateKey)
+	}
+
+	return nil, fmt.Errorf("unknown token maker %q: must be %s, %s or %s", conf.Kind, KindPaseto, KindJWT, KindEdDSA)
+}
+
+// newKeyringMaker returns the maker of the keyring, its kind must be the configured one.
+func newKeyringMaker(conf Config) (Maker, error) {
+	var (
+		keyring *Keyring
+		err     error
+	)
+	if conf.KeyringFile != "" {
+		keyring, err = LoadKeyring(conf.KeyringFile)
+	} else {
+		keyring, err = ParseKeyring([]byte(conf.Keyring))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kind := conf.Kind
+	if kind == "" {
+		kind = KindPaseto
+	}
+
+	if keyring.Kind != kind {
+		return nil, fmt.Errorf("keyring of %s keys can't be used by the %s maker", keyring.Kind, kind)
+	}
+
+	if conf.KeyringFile != "" {
+		return NewKeyringFileMaker(conf.KeyringFile, conf.GracePeriod)
+	}
+
+	return NewKeyringMaker(keyring, conf.GracePeriod)
+}
@@ -0,0 +1,77 @@
+package validations
+
+import (
+	simplebankpb "github.com/orlandorode97/simple-bank/generated/simplebank"
+)
+
+type CloseAccountValidator struct {
+	AccountID      int64  `validate:"required,min=1"`
+	SweepAccountID int64  `validate:"omitempty,min=1"`
+	Reason         string `validate:"max=255"`
+}
+
+func NewCloseAccountValidator(req *simplebankpb.CloseAccountRequest) *CloseAccountValidator {
+	return &CloseAccountValidator{
+		AccountID:      req.GetAccountId(),
+		SweepAccountID: req.GetSweepAccountId(),
+		Reason:         req.GetReason(),
+	}
+}
+
+type SettlementValidator struct {
+	AccountID         int64  `validate:"required,min=1"`
+	Amount            int64  `validate:"required,gt=0"`
+	ExternalReference string `validate:"required,max=64"`
+}
+
+func NewDepositValidator(req *simplebankpb.DepositRequest) *SettlementValidator {
+	return &SettlementValidator{
+		AccountID:         req.GetAccountId(),
+		Amount:            req.GetAmount(),
+		ExternalReference: req.GetExternalReference(),
+	}
+}
+
+func NewWithdrawValidator(req *simplebankpb.WithdrawRequest) *SettlementValidator {
+	return &SettlementValidator{
+		AccountID:         req.GetAccountId(),
+		Amount:            req.GetAmount(),
+		ExternalReference: req.GetExternalReference(),
+	}
+}
+
+type MovePocketFundsValidator struct {
+	FromAccountID int64 `validate:"required,min=1"`
+	ToAccountID   int64 `validate:"required,min=1,nefield=FromAccountID"`
+	Amount        int64 `validate:"required,gt=0"`
+}
+
+func NewMovePocketFundsValidator(req *simplebankpb.MovePocketFundsRequest) *MovePocketFundsValidator {
+	return &MovePocketFundsValidator{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+	}
+}
+
+type LookupAccountValidator struct {
+	AccountID int64 `validate:"required,min=1"`
+}
+
+func NewLookupAccountValidator(req *simplebankpb.LookupAccountRequest) *LookupAccountValidator {
+	return &LookupAccountValidator{
+		AccountID: req.GetAccountId(),
+	}
+}
+
+type FreezeAccountValidator struct {
+	AccountID int64  `validate:"required,min=1"`
+	Reason    string `validate:"required,max=255"`
+}
+
+func NewFreezeAccountValidator(req *simplebankpb.FreezeAccountRequest) *FreezeAccountValidator {
+	return &FreezeAccountValidator{
+		AccountID: req.GetAccountId(),
+		Reason:    req.GetReason(),
+	}
+}
@@ -0,0 +1,114 @@
+package validations
+
+import (
+	simplebankpb "github.com/orlandorode97/simple-bank/generated/simplebank"
+)
+
+type CreateTransferValidator struct {
+	FromAccountID     int64  `validate:"required_without=FromAccountNumber,min=0"`
+	ToAccountID       int64  `validate:"required_without=ToAccountNumber,omitempty,min=0,nefield=FromAccountID"`
+	Amount            int64  `validate:"required,gt=1"`
+	CurrencyID        int64  `validate:"required_without=Currency,min=0"`
+	FromAccountNumber string `validate:"required_without=FromAccountID"`
+	ToAccountNumber   string `validate:"required_without=ToAccountID,omitempty,nefield=FromAccountNumber"`
+	Currency          string `validate:"required_without=CurrencyID"`
+}
+
+func NewCreateTransferValidator(req *simplebankpb.CreateTransferRequest) *CreateTransferValidator {
+	return &CreateTransferValidator{
+		FromAccountID:     req.GetFromAccountId(),
+		ToAccountID:       req.GetToAccountId(),
+		Amount:            req.GetAmount(),
+		CurrencyID:        req.GetCurrencyId(),
+		FromAccountNumber: req.GetFromAccountNumber(),
+		ToAccountNumber:   req.GetToAccountNumber(),
+		Currency:          req.GetCurrency(),
+	}
+}
+
+type ReverseTransferValidator struct {
+	TransferID int64 `validate:"required,min=1"`
+	Amount     int64 `validate:"min=0"`
+}
+
+type AuthorizeTransferValidator struct {
+	CreateTransferValidator
+	ExpiresIn int64 `validate:"omitempty,min=60,max=2592000"`
+}
+
+func NewAuthorizeTransferValidator(req *simplebankpb.AuthorizeTransferRequest) *AuthorizeTransferValidator {
+	return &AuthorizeTransferValidator{
+		CreateTransferValidator: CreateTransferValidator{
+			FromAccountID:     req.GetFromAccountId(),
+			ToAccountID:       req.GetToAccountId(),
+			Amount:            req.GetAmount(),
+			CurrencyID:        req.GetCurrencyId(),
+			FromAccountNumber: req.GetFromAccountNumber(),
+			ToAccountNumber:   req.GetToAccountNumber(),
+			Currency:          req.GetCurrency(),
+		},
+		ExpiresIn: req.GetExpiresIn(),
+	}
+}
+
+type CaptureTransferValidator struct {
+	TransferID int64 `validate:"required,min=1"`
+	Amount     int64 `validate:"min=0"`
+}
+
+func NewCaptureTransferValidator(req *simplebankpb.CaptureTransferRequest) *CaptureTransferValidator {
+	return &CaptureTransferValidator{
+		TransferID: req.GetTransferId(),
+		Amount:     req.GetAmount(),
+	}
+}
+
+type VoidTransferValidator struct {
+	TransferID int64 `validate:"required,min=1"`
+}
+
+func NewVoidTransferValidator(req *simplebankpb.VoidTransferRequest) *VoidTransferValidator {
+	return &VoidTransferValidator{
+		TransferID: req.GetTransferId(),
+	}
+}
+
+func NewReverseTransferValidator(req *simplebankpb.ReverseTransferRequest) *ReverseTransferValidator {
+	return &ReverseTransferValidator{
+		TransferID: req.GetTransferId(),
+		Amount:     req.GetAmount(),
+	}
+}
+
+type BatchTransferLegValidator struct {
+	ToAccountID     int64  `validate:"required_without=ToAccountNumber,min=0"`
+	Amount          int64  `validate:"required,gt=1"`
+	ToAccountNumber string `validate:"required_without=ToAccountID"`
+}
+
+type BatchTransferValidator struct {
+	FromAccountID     int64                       `validate:"required_without=FromAccountNumber,min=0"`
+	CurrencyID        int64                       `validate:"required_without=Currency,min=0"`
+	Legs              []BatchTransferLegValidator `validate:"required,min=1,max=100,dive"`
+	FromAccountNumber string                      `validate:"required_without=FromAccountID"`
+	Currency          string                      `validate:"required_without=CurrencyID"`
+}
+
+func NewBatchTransferValidator(req *simplebankpb.BatchTransferRequest) *BatchTransferValidator {
+	legs := make([]BatchTransferLegValidator, 0, len(req.GetLegs()))
+	for _, leg := range req.GetLegs() {
+		legs = append(legs, BatchTransferLegValidator{
+			ToAccountID:     leg.GetToAccountId(),
+			Amount:          leg.GetAmount(),
+			ToAccountNumber: leg.GetToAccountNumber(),
+		})
+	}
+
+	return &BatchTransferValidator{
+		FromAccountID:     req.GetFromAccountId(),
+		CurrencyID:        req.GetCurrencyId(),
+		Legs:              legs,
+		FromAccountNumber: req.GetFromAccountNumber(),
+		Currency:          req.GetCurrency(),
+	}
+}
@@ -45,3 +45,49 @@ func NewUpdateUserValidator(req *simplebankpb.UpdateUserRequest) *UpdateUserVali
 		Password: req.GetPassword(),
 	}
 }
+
+type RevokeSessionValidator struct {
+	SessionID string `validate:"required,uuid"`
+}
+
+func NewRevokeSessionValidator(req *simplebankpb.RevokeSessionRequest) *RevokeSessionValidator {
+	return &RevokeSessionValidator{
+		SessionID: req.GetSessionId(),
+	}
+}
+
+type SearchUsersValidator struct {
+	Query    string `validate:"required,min=2"`
+	PageID   int32  `validate:"required,min=1"`
+	PageSize int32  `validate:"required,min=5,max=50"`
+}
+
+func NewSearchUsersValidator(req *simplebankpb.SearchUsersRequest) *SearchUsersValidator {
+	return &SearchUsersValidator{
+		Query:    req.GetQuery(),
+		PageID:   req.GetPageId(),
+		PageSize: req.GetPageSize(),
+	}
+}
+
+type UpdateUserRoleValidator struct {
+	Username string `validate:"required,alphanum"`
+	Role     string `validate:"required,oneof=customer teller admin"`
+}
+
+func NewUpdateUserRoleValidator(req *simplebankpb.UpdateUserRoleRequest) *UpdateUserRoleValidator {
+	return &UpdateUserRoleValidator{
+		Username: req.GetUsername(),
+		Role:     req.GetRole(),
+	}
+}
+
+type BlockUserSessionsValidator struct {
+	Username string `validate:"required,alphanum"`
+}
+
+func NewBlockUserSessionsValidator(req *simplebankpb.BlockUserSessionsRequest) *BlockUserSessionsValidator {
+	return &BlockUserSessionsValidator{
+		Username: req.GetUsername(),
+	}
+}
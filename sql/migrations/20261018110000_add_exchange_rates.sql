@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "exchange_rates" (
+  "id" bigserial PRIMARY KEY,
+  "from_currency_id" bigint NOT NULL,
+  "to_currency_id" bigint NOT NULL,
+  "rate" numeric(20, 10) NOT NULL,
+  "effective_at" timestamptz NOT NULL DEFAULT (now()),
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "exchange_rates" ("from_currency_id", "to_currency_id", "effective_at");
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'minor units of to_currency credited per minor unit of from_currency';
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("from_currency_id") REFERENCES "currencies" ("id");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("to_currency_id") REFERENCES "currencies" ("id");
+
+ALTER TABLE "transfers" ADD COLUMN "to_amount" bigint;
+
+UPDATE "transfers" SET "to_amount" = "amount";
+
+ALTER TABLE "transfers" ALTER COLUMN "to_amount" SET NOT NULL;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" numeric(20, 10) NOT NULL DEFAULT 1;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate_id" bigint;
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'amount credited in the currency of the to account';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("exchange_rate_id") REFERENCES "exchange_rates" ("id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS public.transfers DROP COLUMN IF EXISTS "exchange_rate_id";
+ALTER TABLE IF EXISTS public.transfers DROP COLUMN IF EXISTS "exchange_rate";
+ALTER TABLE IF EXISTS public.transfers DROP COLUMN IF EXISTS "to_amount";
+DROP TABLE IF EXISTS public.exchange_rates CASCADE;
+-- +goose StatementEnd
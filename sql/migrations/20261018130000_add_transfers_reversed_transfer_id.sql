@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "transfers" ADD COLUMN "reversed_transfer_id" bigint;
+
+CREATE INDEX ON "transfers" ("reversed_transfer_id");
+
+COMMENT ON COLUMN "transfers"."reversed_transfer_id" IS 'transfer compensated by this reversal';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversed_transfer_id") REFERENCES "transfers" ("id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS public.transfers DROP COLUMN IF EXISTS "reversed_transfer_id";
+-- +goose StatementEnd
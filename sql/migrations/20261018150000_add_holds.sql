@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE transfer_status AS ENUM (
+  'pending',
+  'posted',
+  'voided',
+  'expired'
+);
+
+ALTER TABLE "transfers" ADD COLUMN "status" transfer_status NOT NULL DEFAULT 'posted';
+
+COMMENT ON COLUMN "transfers"."status" IS 'pending transfers hold funds until they are captured, voided or expired';
+
+ALTER TABLE "accounts" ADD COLUMN "held_balance" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "accounts"."held_balance" IS 'funds reserved by active holds, it lowers the available balance but not the balance';
+
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint UNIQUE NOT NULL,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "released_at" timestamptz,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "holds" ("account_id");
+
+CREATE INDEX ON "holds" ("expires_at") WHERE "released_at" IS NULL;
+
+COMMENT ON COLUMN "holds"."released_at" IS 'set once the hold is captured, voided or expired';
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS holds;
+
+ALTER TABLE IF EXISTS public.accounts DROP COLUMN IF EXISTS "held_balance";
+
+ALTER TABLE IF EXISTS public.transfers DROP COLUMN IF EXISTS "status";
+
+DROP TYPE IF EXISTS transfer_status;
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer that posted the entry';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+-- Transfers used to be created outside the db transaction of their entries, so legacy entries are matched
+-- by account and amount to a transfer created a few seconds around them. Every transfer gets at most one
+-- debit and one credit and every entry at most one transfer, the closest in time on both sides.
+WITH candidates AS (
+  SELECT
+    e."id" AS entry_id,
+    t."id" AS transfer_id,
+    e."amount" < 0 AS debit,
+    abs(extract(epoch FROM e."createad_at" - t."createad_at")) AS distance
+  FROM "entries" e
+  JOIN "transfers" t
+    ON t."createad_at" BETWEEN e."createad_at" - interval '5 seconds' AND e."createad_at" + interval '5 seconds'
+    AND (
+      (e."account_id" = t."from_account_id" AND e."amount" = -t."amount") OR
+      (e."account_id" = t."to_account_id" AND e."amount" = t."to_amount")
+    )
+  WHERE e."transfer_id" IS NULL
+), ranked AS (
+  SELECT
+    entry_id,
+    transfer_id,
+    row_number() OVER (PARTITION BY entry_id ORDER BY distance, transfer_id) AS entry_rank,
+    row_number() OVER (PARTITION BY transfer_id, debit ORDER BY distance, entry_id) AS transfer_rank
+  FROM candidates
+)
+UPDATE "entries" e
+SET "transfer_id" = r.transfer_id
+FROM ranked r
+WHERE e."id" = r.entry_id
+  AND r.entry_rank = 1
+  AND r.transfer_rank = 1;
+
+CREATE TABLE "reconciliation_reports" (
+  "id" bigserial PRIMARY KEY,
+  "drifted_accounts" int NOT NULL,
+  "orphaned_entries" int NOT NULL,
+  "unbalanced_transfers" int NOT NULL,
+  "details" jsonb NOT NULL,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "reconciliation_reports"."details" IS 'drifted accounts, orphaned entries and unbalanced transfers found';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reconciliation_reports;
+
+ALTER TABLE IF EXISTS public.entries DROP COLUMN IF EXISTS "transfer_id";
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE system_account_kind AS ENUM (
+  'cash_in',
+  'cash_out',
+  'suspense'
+);
+
+ALTER TABLE "accounts" ADD COLUMN "system_kind" system_account_kind;
+
+CREATE UNIQUE INDEX ON "accounts" ("system_kind", "currency_id") WHERE "system_kind" IS NOT NULL;
+
+COMMENT ON COLUMN "accounts"."system_kind" IS 'settlement account of the bank, deposits and withdrawals post against it';
+
+ALTER TABLE "transfers" ADD COLUMN "external_reference" varchar;
+
+CREATE UNIQUE INDEX ON "transfers" ("external_reference");
+
+COMMENT ON COLUMN "transfers"."external_reference" IS 'reference of the deposit or withdrawal in the external payment system';
+
+-- Every kind of system account is owned by its own system user, since an owner holds one account per currency.
+-- The password hash is not a bcrypt hash, so system users can't log in.
+INSERT INTO users (username, hashed_password, full_name, email)
+SELECT 'system_' || kind, '!', 'System ' || kind || ' account', 'system_' || kind || '@simplebank.local'
+FROM unnest(enum_range(NULL::system_account_kind)) AS kind;
+
+INSERT INTO accounts (owner, balance, currency_id, system_kind)
+SELECT 'system_' || kind, 0, currencies.id, kind
+FROM currencies CROSS JOIN unnest(enum_range(NULL::system_account_kind)) AS kind;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- System accounts and their ledger history are kept as regular accounts.
+ALTER TABLE IF EXISTS public.transfers DROP COLUMN IF EXISTS "external_reference";
+
+ALTER TABLE IF EXISTS public.accounts DROP COLUMN IF EXISTS "system_kind";
+
+DROP TYPE IF EXISTS system_account_kind;
+-- +goose StatementEnd
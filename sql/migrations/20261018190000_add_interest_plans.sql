@@ -0,0 +1,66 @@
+-- +goose NO TRANSACTION
+-- The new enum value can't be used within the transaction that adds it.
+-- +goose Up
+ALTER TYPE system_account_kind ADD VALUE 'interest_expense';
+
+CREATE TABLE "interest_plans" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar UNIQUE NOT NULL,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_plan_tiers" (
+  "id" bigserial PRIMARY KEY,
+  "plan_id" bigint NOT NULL,
+  "min_balance" bigint NOT NULL,
+  "annual_rate" numeric(10,6) NOT NULL,
+  UNIQUE ("plan_id", "min_balance")
+);
+
+COMMENT ON COLUMN "interest_plan_tiers"."annual_rate" IS 'applies to the part of the balance from min_balance up to the next tier';
+
+ALTER TABLE "interest_plan_tiers" ADD FOREIGN KEY ("plan_id") REFERENCES "interest_plans" ("id");
+
+ALTER TABLE "accounts" ADD COLUMN "interest_plan_id" bigint;
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("interest_plan_id") REFERENCES "interest_plans" ("id");
+
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "plan_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "amount" numeric(24,10) NOT NULL,
+  "transfer_id" bigint,
+  "createad_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("account_id", "accrual_date")
+);
+
+CREATE INDEX ON "interest_accruals" ("account_id") WHERE "transfer_id" IS NULL;
+
+COMMENT ON COLUMN "interest_accruals"."amount" IS 'interest of the day in minor units, it is rounded once the month is capitalized';
+
+COMMENT ON COLUMN "interest_accruals"."transfer_id" IS 'transfer that capitalized the accrual';
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("plan_id") REFERENCES "interest_plans" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+INSERT INTO users (username, hashed_password, full_name, email)
+VALUES ('system_interest_expense', '!', 'System interest_expense account', 'system_interest_expense@simplebank.local');
+
+INSERT INTO accounts (owner, balance, currency_id, system_kind)
+SELECT 'system_interest_expense', 0, currencies.id, 'interest_expense'
+FROM currencies;
+
+-- +goose Down
+DROP TABLE IF EXISTS interest_accruals;
+
+ALTER TABLE IF EXISTS public.accounts DROP COLUMN IF EXISTS "interest_plan_id";
+
+DROP TABLE IF EXISTS interest_plan_tiers;
+
+DROP TABLE IF EXISTS interest_plans;
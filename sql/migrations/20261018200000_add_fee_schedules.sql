@@ -0,0 +1,65 @@
+-- +goose NO TRANSACTION
+-- The new enum value can't be used within the transaction that adds it.
+-- +goose Up
+ALTER TYPE system_account_kind ADD VALUE 'fee_revenue';
+
+CREATE TYPE account_tier AS ENUM (
+  'standard',
+  'premium',
+  'business'
+);
+
+ALTER TABLE "accounts" ADD COLUMN "tier" account_tier NOT NULL DEFAULT 'standard';
+
+CREATE TABLE "fee_schedules" (
+  "id" bigserial PRIMARY KEY,
+  "currency_id" bigint NOT NULL,
+  "account_tier" account_tier,
+  "flat_amount" bigint NOT NULL DEFAULT 0,
+  "percentage" numeric(10,6) NOT NULL DEFAULT 0,
+  "min_fee" bigint NOT NULL DEFAULT 0,
+  "max_fee" bigint,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "fee_schedules" ("currency_id", "account_tier") WHERE "account_tier" IS NOT NULL;
+
+CREATE UNIQUE INDEX ON "fee_schedules" ("currency_id") WHERE "account_tier" IS NULL;
+
+COMMENT ON COLUMN "fee_schedules"."account_tier" IS 'tier of the from account, the schedule applies to every tier without one of its own when it is null';
+
+COMMENT ON COLUMN "fee_schedules"."percentage" IS 'share of the transfer amount such as 0.015 for 1.5%, added to the flat amount';
+
+COMMENT ON COLUMN "fee_schedules"."max_fee" IS 'the fee is not capped when it is null';
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("currency_id") REFERENCES "currencies" ("id");
+
+ALTER TABLE "transfers" ADD COLUMN "charged_transfer_id" bigint;
+
+ALTER TABLE "transfers" ADD COLUMN "fee_schedule_id" bigint;
+
+CREATE INDEX ON "transfers" ("charged_transfer_id");
+
+COMMENT ON COLUMN "transfers"."charged_transfer_id" IS 'transfer this fee was charged for';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("charged_transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("fee_schedule_id") REFERENCES "fee_schedules" ("id");
+
+INSERT INTO users (username, hashed_password, full_name, email)
+VALUES ('system_fee_revenue', '!', 'System fee_revenue account', 'system_fee_revenue@simplebank.local');
+
+INSERT INTO accounts (owner, balance, currency_id, system_kind)
+SELECT 'system_fee_revenue', 0, currencies.id, 'fee_revenue'
+FROM currencies;
+
+-- +goose Down
+ALTER TABLE IF EXISTS public.transfers DROP COLUMN IF EXISTS "fee_schedule_id";
+
+ALTER TABLE IF EXISTS public.transfers DROP COLUMN IF EXISTS "charged_transfer_id";
+
+DROP TABLE IF EXISTS fee_schedules;
+
+ALTER TABLE IF EXISTS public.accounts DROP COLUMN IF EXISTS "tier";
+
+DROP TYPE IF EXISTS account_tier;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "accounts" ADD COLUMN "name" varchar NOT NULL DEFAULT 'Main';
+
+COMMENT ON COLUMN "accounts"."name" IS 'name of the pocket, an owner can hold several accounts in the same currency';
+
+ALTER TABLE IF EXISTS public.accounts DROP CONSTRAINT IF EXISTS "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_currency_name_key" ON "accounts" ("owner", "currency_id", "name") WHERE "status" <> 'closed';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "owner_currency_name_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE("owner", "currency_id");
+
+ALTER TABLE IF EXISTS public.accounts DROP COLUMN IF EXISTS "name";
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE account_member_role AS ENUM (
+  'owner',
+  'full_access',
+  'view_only',
+  'spend_limit'
+);
+
+CREATE TABLE "account_members" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" account_member_role NOT NULL,
+  "spend_limit" bigint,
+  "invited_by" varchar NOT NULL,
+  "accepted_at" timestamptz,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "account_members" ("account_id", "username");
+
+CREATE INDEX ON "account_members" ("username");
+
+COMMENT ON COLUMN "account_members"."spend_limit" IS 'largest amount a spend_limit member can move in a single operation';
+
+COMMENT ON COLUMN "account_members"."accepted_at" IS 'the member has no access until the invitation is accepted';
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("invited_by") REFERENCES "users" ("username");
+
+INSERT INTO account_members (account_id, username, role, invited_by, accepted_at)
+SELECT id, owner, 'owner', owner, createad_at
+FROM accounts
+WHERE system_kind IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS account_members;
+
+DROP TYPE IF EXISTS account_member_role;
+-- +goose StatementEnd
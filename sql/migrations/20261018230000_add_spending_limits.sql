@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "transfers" ADD COLUMN "initiated_by" varchar;
+
+COMMENT ON COLUMN "transfers"."initiated_by" IS 'user who initiated the transfer, only these transfers count towards the spending limits';
+
+CREATE INDEX ON "transfers" ("initiated_by", "createad_at");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
+
+CREATE TABLE "spending_limits" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "currency_id" bigint NOT NULL,
+  "daily_limit" bigint,
+  "monthly_limit" bigint,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "spending_limits" ("username", "currency_id");
+
+COMMENT ON COLUMN "spending_limits"."daily_limit" IS 'overrides the default of the currency when it is not null, zero means no limit';
+
+COMMENT ON COLUMN "spending_limits"."monthly_limit" IS 'overrides the default of the currency when it is not null, zero means no limit';
+
+ALTER TABLE "spending_limits" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "spending_limits" ADD FOREIGN KEY ("currency_id") REFERENCES "currencies" ("id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS spending_limits;
+
+ALTER TABLE IF EXISTS public.transfers DROP COLUMN IF EXISTS "initiated_by";
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- generate_account_number returns a random account number such as SB06123456789012,
+-- its check digits are computed as in an IBAN, S is 28 and B is 11.
+CREATE FUNCTION generate_account_number() RETURNS varchar AS $$
+DECLARE
+  basic varchar := lpad(floor(random() * 1000000000000)::bigint::text, 12, '0');
+BEGIN
+  RETURN 'SB' || lpad((98 - (basic || '281100')::numeric % 97)::text, 2, '0') || basic;
+END;
+$$ LANGUAGE plpgsql VOLATILE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE "accounts" ADD COLUMN "account_number" varchar NOT NULL DEFAULT (generate_account_number());
+
+CREATE UNIQUE INDEX ON "accounts" ("account_number");
+
+COMMENT ON COLUMN "accounts"."account_number" IS 'external number of the account with mod 97 check digits, clients use it instead of the id';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS public.accounts DROP COLUMN IF EXISTS "account_number";
+
+DROP FUNCTION IF EXISTS generate_account_number();
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "currencies" ALTER COLUMN "name" TYPE varchar USING "name"::text;
+
+DROP TYPE currencies;
+
+ALTER TABLE "currencies" RENAME COLUMN "name" TO "code";
+
+UPDATE currencies SET code = 'MXN' WHERE code = 'MXM';
+
+ALTER TABLE "currencies"
+  ADD COLUMN "exponent" smallint NOT NULL DEFAULT 2,
+  ADD COLUMN "symbol" varchar NOT NULL DEFAULT '',
+  ADD COLUMN "enabled" boolean NOT NULL DEFAULT true,
+  ADD COLUMN "createad_at" timestamptz NOT NULL DEFAULT (now());
+
+ALTER TABLE "currencies" ADD CONSTRAINT "currencies_code_check" CHECK ("code" ~ '^[A-Z]{3}$');
+
+ALTER TABLE "currencies" ADD CONSTRAINT "currencies_exponent_check" CHECK ("exponent" BETWEEN 0 AND 4);
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 alphabetic code';
+
+COMMENT ON COLUMN "currencies"."exponent" IS 'number of decimals of the minor unit, amounts are stored in minor units';
+
+COMMENT ON COLUMN "currencies"."enabled" IS 'new accounts and transfers are only allowed in enabled currencies';
+
+UPDATE currencies SET symbol = '$' WHERE code IN ('USD', 'MXN', 'CAD');
+
+UPDATE currencies SET symbol = '€' WHERE code = 'EUR';
+
+UPDATE currencies SET symbol = '¥', exponent = 0 WHERE code = 'JPY';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Currencies added to the catalog afterwards are not part of the enum, they must be removed before going down.
+ALTER TABLE IF EXISTS public.currencies DROP CONSTRAINT IF EXISTS "currencies_code_check";
+
+ALTER TABLE IF EXISTS public.currencies DROP CONSTRAINT IF EXISTS "currencies_exponent_check";
+
+ALTER TABLE IF EXISTS public.currencies
+  DROP COLUMN IF EXISTS "exponent",
+  DROP COLUMN IF EXISTS "symbol",
+  DROP COLUMN IF EXISTS "enabled",
+  DROP COLUMN IF EXISTS "createad_at";
+
+UPDATE currencies SET code = 'MXM' WHERE code = 'MXN';
+
+ALTER TABLE IF EXISTS public.currencies RENAME COLUMN "code" TO "name";
+
+CREATE TYPE currencies AS ENUM (
+  'USD',
+  'EUR',
+  'MXM',
+  'CAD',
+  'JPY'
+);
+
+ALTER TABLE IF EXISTS public.currencies ALTER COLUMN "name" TYPE currencies USING "name"::currencies;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "sessions" ADD COLUMN "family_id" uuid;
+
+UPDATE sessions SET family_id = id;
+
+ALTER TABLE "sessions" ALTER COLUMN "family_id" SET NOT NULL;
+
+ALTER TABLE "sessions" ADD COLUMN "parent_id" uuid;
+
+ALTER TABLE "sessions" ADD COLUMN "rotated_at" timestamptz;
+
+CREATE INDEX ON "sessions" ("family_id");
+
+ALTER TABLE "sessions" ADD FOREIGN KEY ("parent_id") REFERENCES "sessions" ("id");
+
+COMMENT ON COLUMN "sessions"."family_id" IS 'id of the session created at login, every session rotated from it shares it';
+
+COMMENT ON COLUMN "sessions"."parent_id" IS 'session rotated into this one';
+
+COMMENT ON COLUMN "sessions"."rotated_at" IS 'when the refresh token was exchanged, presenting it again revokes the whole family';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS public.sessions DROP COLUMN IF EXISTS "rotated_at";
+
+ALTER TABLE IF EXISTS public.sessions DROP COLUMN IF EXISTS "parent_id";
+
+ALTER TABLE IF EXISTS public.sessions DROP COLUMN IF EXISTS "family_id";
+-- +goose StatementEnd
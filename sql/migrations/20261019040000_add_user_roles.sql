@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE user_role AS ENUM (
+  'customer',
+  'teller',
+  'admin'
+);
+
+ALTER TABLE "users" ADD COLUMN "role" user_role NOT NULL DEFAULT 'customer';
+
+COMMENT ON COLUMN "users"."role" IS 'tellers can search users and view any account, admins can also freeze accounts, block sessions and manage the catalogs';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS public.users DROP COLUMN IF EXISTS "role";
+
+DROP TYPE IF EXISTS user_role;
+-- +goose StatementEnd
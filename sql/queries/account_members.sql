@@ -0,0 +1,32 @@
+-- name: CreateAccountMember :one
+INSERT INTO account_members (
+  account_id, username, role, spend_limit, invited_by, accepted_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetAccountMember :one
+SELECT * FROM account_members
+WHERE account_id = $1 AND username = $2 LIMIT 1;
+
+-- name: ListAccountMembers :many
+SELECT * FROM account_members
+WHERE account_id = $1
+ORDER BY id;
+
+-- name: ListPendingInvitations :many
+SELECT * FROM account_members
+WHERE username = $1 AND accepted_at IS NULL
+ORDER BY id;
+
+-- name: AcceptAccountMember :one
+UPDATE account_members
+SET accepted_at = now()
+WHERE account_id = $1 AND username = $2 AND accepted_at IS NULL
+RETURNING *;
+
+-- name: DeleteAccountMember :one
+DELETE FROM account_members
+WHERE account_id = $1 AND username = $2
+RETURNING *;
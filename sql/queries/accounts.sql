@@ -1,8 +1,8 @@
 -- name: CreateAccount :one
 INSERT INTO accounts (
-  owner, balance, currency_id
+  owner, balance, currency_id, name
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 )
 RETURNING *;
 
@@ -11,30 +11,80 @@ SELECT * FROM accounts
 WHERE id = $1 LIMIT 1;
 
 
+-- name: GetAccountByNumber :one
+SELECT * FROM accounts
+WHERE account_number = $1 LIMIT 1;
+
 -- name: GetAccountForUpdate :one
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE;
 
 -- name: ListAccounts :many
-SELECT * FROM accounts
-WHERE owner = $1
-ORDER BY id
+SELECT a.* FROM accounts a
+JOIN account_members m ON m.account_id = a.id
+WHERE m.username = sqlc.arg(username) AND m.accepted_at IS NOT NULL
+ORDER BY a.currency_id, a.id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: AddAccountBalance :one
+UPDATE accounts
+SET balance = balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddAccountHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateAccountStatusChange :one
+INSERT INTO account_status_changes (
+  account_id, from_status, to_status, changed_by, reason
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ListAccountStatusChanges :many
+SELECT * FROM account_status_changes
+WHERE account_id = $1
+ORDER BY id DESC
 LIMIT $2
 OFFSET $3;
 
--- name: UpdateAccount :one
+-- name: GetSystemAccount :one
+SELECT * FROM accounts
+WHERE system_kind = $1 AND currency_id = $2 LIMIT 1;
+
+-- name: CreateSystemAccounts :many
+INSERT INTO accounts (owner, balance, currency_id, system_kind)
+SELECT 'system_' || kind, 0, sqlc.arg(currency_id)::bigint, kind
+FROM unnest(enum_range(NULL::system_account_kind)) AS kind
+RETURNING *;
+
+-- name: UpdateAccountName :one
 UPDATE accounts
-SET balance = $2
+SET name = $2
 WHERE id = $1
 RETURNING *;
 
--- name: AddAccountBalance :one
+-- name: UpdateAccountTier :one
 UPDATE accounts
-SET balance = balance + sqlc.arg(amount)
-WHERE id = sqlc.arg(id)
+SET tier = $2
+WHERE id = $1
 RETURNING *;
-
--- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1;
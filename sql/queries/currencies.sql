@@ -1,6 +1,27 @@
 -- name: CreateCurrency :one
 INSERT INTO currencies (
-  name
-) VALUES ( $1 )
+  code, exponent, symbol, enabled
+) VALUES ( $1, $2, $3, $4 )
 RETURNING *;
 
+
+-- name: GetCurrency :one
+SELECT * FROM currencies
+WHERE id = $1 LIMIT 1;
+
+-- name: GetCurrencyByCode :one
+SELECT * FROM currencies
+WHERE code = $1 LIMIT 1;
+
+-- name: ListCurrencies :many
+SELECT * FROM currencies
+ORDER BY id;
+
+-- name: UpdateCurrency :one
+UPDATE currencies
+SET
+  symbol = COALESCE(sqlc.narg(symbol), symbol),
+  enabled = COALESCE(sqlc.narg(enabled), enabled)
+WHERE
+  id = sqlc.arg(id)
+RETURNING *;
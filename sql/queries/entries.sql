@@ -1,7 +1,7 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
-  account_id, amount
-) VALUES ( $1, $2 )
+  account_id, amount, transfer_id
+) VALUES ( $1, $2, $3 )
 RETURNING *;
 
 -- name: GetEntry :one
@@ -18,3 +18,55 @@ OFFSET $2;
 DELETE FROM entries
 WHERE id = $1;
 
+
+-- name: ListAccountEntries :many
+-- counterparty_account_id is 0 for entries that were not posted by a transfer. The running balance is summed
+-- over the whole history of the account once, before the entries are filtered and paginated.
+SELECT
+  e.id, e.account_id, e.amount, e.createad_at, e.transfer_id, e.running_balance,
+  COALESCE(
+    CASE WHEN t.from_account_id = e.account_id THEN t.to_account_id ELSE t.from_account_id END, 0
+  )::bigint AS counterparty_account_id
+FROM (
+  SELECT
+    x.*,
+    SUM(x.amount) OVER (PARTITION BY x.account_id ORDER BY x.id)::bigint AS running_balance
+  FROM entries x
+  WHERE x.account_id = sqlc.arg(account_id)
+) e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE
+  (sqlc.narg(from_date)::timestamptz IS NULL OR e.createad_at >= sqlc.narg(from_date)::timestamptz) AND
+  (sqlc.narg(to_date)::timestamptz IS NULL OR e.createad_at < sqlc.narg(to_date)::timestamptz) AND
+  (sqlc.narg(direction)::text IS NULL OR
+    (sqlc.narg(direction)::text = 'credit' AND e.amount > 0) OR
+    (sqlc.narg(direction)::text = 'debit' AND e.amount < 0))
+ORDER BY e.id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: GetAccountBalanceAt :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS balance FROM entries
+WHERE account_id = sqlc.arg(account_id) AND createad_at < sqlc.arg(at)::timestamptz;
+
+-- name: CountAccountEntries :one
+SELECT COUNT(*) FROM entries
+WHERE
+  account_id = sqlc.arg(account_id) AND
+  createad_at >= sqlc.arg(from_date)::timestamptz AND
+  createad_at < sqlc.arg(to_date)::timestamptz;
+
+-- name: ListStatementEntries :many
+-- counterparty_account_id is 0 for entries that were not posted by a transfer.
+SELECT
+  e.*,
+  COALESCE(
+    CASE WHEN t.from_account_id = e.account_id THEN t.to_account_id ELSE t.from_account_id END, 0
+  )::bigint AS counterparty_account_id
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE
+  e.account_id = sqlc.arg(account_id) AND
+  e.createad_at >= sqlc.arg(from_date)::timestamptz AND
+  e.createad_at < sqlc.arg(to_date)::timestamptz
+ORDER BY e.id;
@@ -0,0 +1,11 @@
+-- name: CreateExchangeRate :one
+INSERT INTO exchange_rates (
+  from_currency_id, to_currency_id, rate, effective_at
+) VALUES ( $1, $2, $3, $4 )
+RETURNING *;
+
+-- name: GetExchangeRate :one
+SELECT * FROM exchange_rates
+WHERE from_currency_id = $1 AND to_currency_id = $2 AND effective_at <= now()
+ORDER BY effective_at DESC
+LIMIT 1;
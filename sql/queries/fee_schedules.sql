@@ -0,0 +1,16 @@
+-- name: CreateFeeSchedule :one
+INSERT INTO fee_schedules (
+  currency_id, account_tier, flat_amount, percentage, min_fee, max_fee
+) VALUES ( $1, $2, $3, $4, $5, $6 )
+RETURNING *;
+
+-- name: GetFeeSchedule :one
+-- The schedule of the account tier takes precedence over the schedule of the currency for every tier.
+SELECT * FROM fee_schedules
+WHERE currency_id = sqlc.arg(currency_id) AND (account_tier = sqlc.arg(account_tier)::account_tier OR account_tier IS NULL)
+ORDER BY account_tier IS NULL
+LIMIT 1;
+
+-- name: ListFeeSchedules :many
+SELECT * FROM fee_schedules
+ORDER BY currency_id, account_tier NULLS FIRST;
@@ -0,0 +1,21 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+  transfer_id, account_id, amount, expires_at
+) VALUES ( $1, $2, $3, $4 )
+RETURNING *;
+
+-- name: GetHoldByTransfer :one
+SELECT * FROM holds
+WHERE transfer_id = $1 LIMIT 1;
+
+-- name: ReleaseHold :one
+UPDATE holds
+SET released_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListExpiredHolds :many
+SELECT * FROM holds
+WHERE released_at IS NULL AND expires_at <= now()
+ORDER BY expires_at
+LIMIT $1;
@@ -0,0 +1,17 @@
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE username = $1 AND key = $2 LIMIT 1;
+
+-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (
+  username, key, request_hash, transfer_id, response, expires_at
+) VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (username, key) DO UPDATE
+SET
+  request_hash = EXCLUDED.request_hash,
+  transfer_id = EXCLUDED.transfer_id,
+  response = EXCLUDED.response,
+  expires_at = EXCLUDED.expires_at,
+  createad_at = now()
+WHERE idempotency_keys.expires_at < now()
+RETURNING *;
@@ -0,0 +1,63 @@
+-- name: CreateInterestPlan :one
+INSERT INTO interest_plans (
+  name
+) VALUES ( $1 )
+RETURNING *;
+
+-- name: GetInterestPlan :one
+SELECT * FROM interest_plans
+WHERE id = $1 LIMIT 1;
+
+-- name: ListInterestPlans :many
+SELECT * FROM interest_plans
+ORDER BY id;
+
+-- name: CreateInterestPlanTier :one
+INSERT INTO interest_plan_tiers (
+  plan_id, min_balance, annual_rate
+) VALUES ( $1, $2, $3 )
+RETURNING *;
+
+-- name: ListInterestPlanTiers :many
+SELECT * FROM interest_plan_tiers
+WHERE plan_id = $1
+ORDER BY min_balance;
+
+-- name: UpdateAccountInterestPlan :one
+UPDATE accounts
+SET interest_plan_id = sqlc.narg(interest_plan_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListInterestBearingAccounts :many
+-- Accounts are paginated by id, so a run can walk all of them without skipping any.
+SELECT * FROM accounts
+WHERE interest_plan_id IS NOT NULL AND status <> 'closed' AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
+
+-- name: CreateInterestAccrual :one
+-- The accrual of a day is created only once, a conflict returns no rows.
+INSERT INTO interest_accruals (
+  account_id, plan_id, accrual_date, balance, amount
+) VALUES ( $1, $2, $3, $4, $5 )
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING *;
+
+-- name: ListAccountsWithPendingAccruals :many
+SELECT account_id FROM interest_accruals
+WHERE transfer_id IS NULL AND accrual_date < sqlc.arg(before)::date
+GROUP BY account_id
+ORDER BY account_id;
+
+-- name: SumPendingAccruals :one
+SELECT
+  COALESCE(SUM(amount), 0)::numeric AS amount,
+  COUNT(*) AS accruals
+FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id) AND transfer_id IS NULL AND accrual_date < sqlc.arg(before)::date;
+
+-- name: CapitalizeAccruals :exec
+UPDATE interest_accruals
+SET transfer_id = sqlc.arg(transfer_id)
+WHERE account_id = sqlc.arg(account_id) AND transfer_id IS NULL AND accrual_date < sqlc.arg(before)::date;
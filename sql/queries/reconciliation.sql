@@ -0,0 +1,42 @@
+-- name: ListDriftedAccounts :many
+SELECT
+  a.id AS account_id,
+  a.balance,
+  COALESCE(SUM(e.amount), 0)::bigint AS entries_balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id;
+
+-- name: ListOrphanedEntries :many
+SELECT e.* FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE t.id IS NULL OR t.status <> 'posted'
+ORDER BY e.id;
+
+-- name: ListUnbalancedTransfers :many
+SELECT
+  t.id AS transfer_id,
+  COUNT(e.id)::bigint AS entries,
+  COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.from_account_id), 0)::bigint AS debited,
+  COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.to_account_id), 0)::bigint AS credited
+FROM transfers t
+LEFT JOIN entries e ON e.transfer_id = t.id
+WHERE t.status = 'posted'
+GROUP BY t.id
+HAVING COUNT(e.id) <> 2
+  OR COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.from_account_id), 0) <> -t.amount
+  OR COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.to_account_id), 0) <> t.to_amount
+ORDER BY t.id;
+
+-- name: CreateReconciliationReport :one
+INSERT INTO reconciliation_reports (
+  drifted_accounts, orphaned_entries, unbalanced_transfers, details
+) VALUES ( $1, $2, $3, $4 )
+RETURNING *;
+
+-- name: GetLatestReconciliationReport :one
+SELECT * FROM reconciliation_reports
+ORDER BY id DESC
+LIMIT 1;
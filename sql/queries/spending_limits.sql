@@ -0,0 +1,38 @@
+-- name: GetSpendingLimit :one
+SELECT * FROM spending_limits
+WHERE username = $1 AND currency_id = $2 LIMIT 1;
+
+-- name: UpsertSpendingLimit :one
+INSERT INTO spending_limits (
+  username, currency_id, daily_limit, monthly_limit
+) VALUES ($1, $2, $3, $4)
+ON CONFLICT (username, currency_id) DO UPDATE
+SET
+  daily_limit = EXCLUDED.daily_limit,
+  monthly_limit = EXCLUDED.monthly_limit
+RETURNING *;
+
+-- name: DeleteSpendingLimit :exec
+DELETE FROM spending_limits
+WHERE username = $1 AND currency_id = $2;
+
+-- name: GetAccountSpending :one
+SELECT
+  COALESCE(SUM(amount) FILTER (WHERE createad_at > sqlc.arg(daily_since)), 0)::bigint AS daily,
+  COALESCE(SUM(amount), 0)::bigint AS monthly
+FROM transfers
+WHERE from_account_id = sqlc.arg(account_id)
+  AND initiated_by IS NOT NULL
+  AND status IN ('pending', 'posted')
+  AND createad_at > sqlc.arg(monthly_since);
+
+-- name: GetUserSpending :one
+SELECT
+  COALESCE(SUM(t.amount) FILTER (WHERE t.createad_at > sqlc.arg(daily_since)), 0)::bigint AS daily,
+  COALESCE(SUM(t.amount), 0)::bigint AS monthly
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE t.initiated_by = sqlc.arg(username)
+  AND a.currency_id = sqlc.arg(currency_id)
+  AND t.status IN ('pending', 'posted')
+  AND t.createad_at > sqlc.arg(monthly_since);
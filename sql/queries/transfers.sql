@@ -1,13 +1,45 @@
 -- name: CreateTransfer :one
 INSERT INTO transfers (
-  from_account_id, to_account_id, amount
-) VALUES ( $1, $2, $3 )
+  from_account_id, to_account_id, amount, to_amount, exchange_rate, exchange_rate_id, reversed_transfer_id, status, external_reference,
+  charged_transfer_id, fee_schedule_id, initiated_by
+) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12 )
 RETURNING *;
 
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferByExternalReference :one
+SELECT * FROM transfers
+WHERE external_reference = $1 LIMIT 1;
+
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetReversedAmounts :one
+SELECT
+  COALESCE(SUM(amount), 0)::bigint AS reversed_amount,
+  COALESCE(SUM(to_amount), 0)::bigint AS refunded_amount
+FROM transfers
+WHERE reversed_transfer_id = sqlc.arg(transfer_id)::bigint;
+
+-- name: CaptureTransfer :one
+UPDATE transfers
+SET
+  amount = sqlc.arg(amount),
+  to_amount = sqlc.arg(to_amount),
+  status = 'posted'
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
 ORDER BY id
@@ -8,6 +8,11 @@ RETURNING *;
 SELECT * FROM users
 WHERE username = $1 LIMIT 1;
 
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: UpdateUser :one
 UPDATE Users
 SET
@@ -19,3 +24,18 @@ WHERE
   username = sqlc.arg(username)
 RETURNING *;
 
+
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE username ILIKE '%' || sqlc.arg(query)::text || '%'
+  OR full_name ILIKE '%' || sqlc.arg(query)::text || '%'
+  OR email ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY username
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE username = $1
+RETURNING *;
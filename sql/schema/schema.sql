@@ -69,8 +69,6 @@ CREATE TABLE "users" (
 
 ALTER TABLE "accounts" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
 
-ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE("owner", "currency_id");
-
 CREATE TABLE "sessions" (
   "id" uuid PRIMARY KEY,
   "username" varchar NOT NULL,
@@ -83,3 +81,421 @@ CREATE TABLE "sessions" (
 );
 
 ALTER TABLE "sessions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+
+CREATE TABLE "idempotency_keys" (
+  "username" varchar NOT NULL,
+  "key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "response" jsonb NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "createad_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "key")
+);
+
+CREATE INDEX ON "idempotency_keys" ("expires_at");
+
+COMMENT ON COLUMN "idempotency_keys"."request_hash" IS 'sha256 of the request body the key was first used with';
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE TABLE "exchange_rates" (
+  "id" bigserial PRIMARY KEY,
+  "from_currency_id" bigint NOT NULL,
+  "to_currency_id" bigint NOT NULL,
+  "rate" numeric(20, 10) NOT NULL,
+  "effective_at" timestamptz NOT NULL DEFAULT (now()),
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "exchange_rates" ("from_currency_id", "to_currency_id", "effective_at");
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'minor units of to_currency credited per minor unit of from_currency';
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("from_currency_id") REFERENCES "currencies" ("id");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("to_currency_id") REFERENCES "currencies" ("id");
+
+ALTER TABLE "transfers" ADD COLUMN "to_amount" bigint NOT NULL;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" numeric(20, 10) NOT NULL DEFAULT 1;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate_id" bigint;
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'amount credited in the currency of the to account';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("exchange_rate_id") REFERENCES "exchange_rates" ("id");
+
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_overdraft_limit_check" CHECK ("overdraft_limit" >= 0);
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'how far below zero the balance is allowed to go';
+
+ALTER TABLE "transfers" ADD COLUMN "reversed_transfer_id" bigint;
+
+CREATE INDEX ON "transfers" ("reversed_transfer_id");
+
+COMMENT ON COLUMN "transfers"."reversed_transfer_id" IS 'transfer compensated by this reversal';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversed_transfer_id") REFERENCES "transfers" ("id");
+
+CREATE TYPE scheduled_transfer_status AS ENUM (
+  'active',
+  'paused',
+  'completed',
+  'cancelled'
+);
+
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "schedule" varchar NOT NULL,
+  "next_run_at" timestamptz NOT NULL,
+  "end_at" timestamptz,
+  "status" scheduled_transfer_status NOT NULL DEFAULT 'active',
+  "failed_attempts" int NOT NULL DEFAULT 0,
+  "last_run_at" timestamptz,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "scheduled_transfers" ("owner");
+
+CREATE INDEX ON "scheduled_transfers" ("status", "next_run_at");
+
+COMMENT ON COLUMN "scheduled_transfers"."schedule" IS 'cron expression or interval rule such as @every 24h';
+
+COMMENT ON COLUMN "scheduled_transfers"."failed_attempts" IS 'consecutive runs that failed after all retries';
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+CREATE TYPE transfer_status AS ENUM (
+  'pending',
+  'posted',
+  'voided',
+  'expired'
+);
+
+ALTER TABLE "transfers" ADD COLUMN "status" transfer_status NOT NULL DEFAULT 'posted';
+
+COMMENT ON COLUMN "transfers"."status" IS 'pending transfers hold funds until they are captured, voided or expired';
+
+ALTER TABLE "accounts" ADD COLUMN "held_balance" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "accounts"."held_balance" IS 'funds reserved by active holds, it lowers the available balance but not the balance';
+
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint UNIQUE NOT NULL,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "released_at" timestamptz,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "holds" ("account_id");
+
+CREATE INDEX ON "holds" ("expires_at") WHERE "released_at" IS NULL;
+
+COMMENT ON COLUMN "holds"."released_at" IS 'set once the hold is captured, voided or expired';
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer that posted the entry';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE TABLE "reconciliation_reports" (
+  "id" bigserial PRIMARY KEY,
+  "drifted_accounts" int NOT NULL,
+  "orphaned_entries" int NOT NULL,
+  "unbalanced_transfers" int NOT NULL,
+  "details" jsonb NOT NULL,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "reconciliation_reports"."details" IS 'drifted accounts, orphaned entries and unbalanced transfers found';
+
+CREATE TYPE account_status AS ENUM (
+  'active',
+  'frozen',
+  'dormant',
+  'closed'
+);
+
+ALTER TABLE "accounts" ADD COLUMN "status" account_status NOT NULL DEFAULT 'active';
+
+COMMENT ON COLUMN "accounts"."status" IS 'frozen and closed accounts cannot send or receive transfers';
+
+CREATE TABLE "account_status_changes" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "from_status" account_status NOT NULL,
+  "to_status" account_status NOT NULL,
+  "changed_by" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "account_status_changes" ("account_id");
+
+COMMENT ON COLUMN "account_status_changes"."changed_by" IS 'username of the owner, or operator name when changed from the command line';
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE TYPE system_account_kind AS ENUM (
+  'cash_in',
+  'cash_out',
+  'suspense'
+);
+
+ALTER TABLE "accounts" ADD COLUMN "system_kind" system_account_kind;
+
+CREATE UNIQUE INDEX ON "accounts" ("system_kind", "currency_id") WHERE "system_kind" IS NOT NULL;
+
+COMMENT ON COLUMN "accounts"."system_kind" IS 'settlement account of the bank, deposits and withdrawals post against it';
+
+ALTER TABLE "transfers" ADD COLUMN "external_reference" varchar;
+
+CREATE UNIQUE INDEX ON "transfers" ("external_reference");
+
+COMMENT ON COLUMN "transfers"."external_reference" IS 'reference of the deposit or withdrawal in the external payment system';
+
+ALTER TYPE system_account_kind ADD VALUE 'interest_expense';
+
+CREATE TABLE "interest_plans" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar UNIQUE NOT NULL,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_plan_tiers" (
+  "id" bigserial PRIMARY KEY,
+  "plan_id" bigint NOT NULL,
+  "min_balance" bigint NOT NULL,
+  "annual_rate" numeric(10,6) NOT NULL,
+  UNIQUE ("plan_id", "min_balance")
+);
+
+COMMENT ON COLUMN "interest_plan_tiers"."annual_rate" IS 'applies to the part of the balance from min_balance up to the next tier';
+
+ALTER TABLE "interest_plan_tiers" ADD FOREIGN KEY ("plan_id") REFERENCES "interest_plans" ("id");
+
+ALTER TABLE "accounts" ADD COLUMN "interest_plan_id" bigint;
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("interest_plan_id") REFERENCES "interest_plans" ("id");
+
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "plan_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "amount" numeric(24,10) NOT NULL,
+  "transfer_id" bigint,
+  "createad_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("account_id", "accrual_date")
+);
+
+CREATE INDEX ON "interest_accruals" ("account_id") WHERE "transfer_id" IS NULL;
+
+COMMENT ON COLUMN "interest_accruals"."amount" IS 'interest of the day in minor units, it is rounded once the month is capitalized';
+
+COMMENT ON COLUMN "interest_accruals"."transfer_id" IS 'transfer that capitalized the accrual';
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("plan_id") REFERENCES "interest_plans" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TYPE system_account_kind ADD VALUE 'fee_revenue';
+
+CREATE TYPE account_tier AS ENUM (
+  'standard',
+  'premium',
+  'business'
+);
+
+ALTER TABLE "accounts" ADD COLUMN "tier" account_tier NOT NULL DEFAULT 'standard';
+
+CREATE TABLE "fee_schedules" (
+  "id" bigserial PRIMARY KEY,
+  "currency_id" bigint NOT NULL,
+  "account_tier" account_tier,
+  "flat_amount" bigint NOT NULL DEFAULT 0,
+  "percentage" numeric(10,6) NOT NULL DEFAULT 0,
+  "min_fee" bigint NOT NULL DEFAULT 0,
+  "max_fee" bigint,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "fee_schedules" ("currency_id", "account_tier") WHERE "account_tier" IS NOT NULL;
+
+CREATE UNIQUE INDEX ON "fee_schedules" ("currency_id") WHERE "account_tier" IS NULL;
+
+COMMENT ON COLUMN "fee_schedules"."account_tier" IS 'tier of the from account, the schedule applies to every tier without one of its own when it is null';
+
+COMMENT ON COLUMN "fee_schedules"."percentage" IS 'share of the transfer amount such as 0.015 for 1.5%, added to the flat amount';
+
+COMMENT ON COLUMN "fee_schedules"."max_fee" IS 'the fee is not capped when it is null';
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("currency_id") REFERENCES "currencies" ("id");
+
+ALTER TABLE "transfers" ADD COLUMN "charged_transfer_id" bigint;
+
+ALTER TABLE "transfers" ADD COLUMN "fee_schedule_id" bigint;
+
+CREATE INDEX ON "transfers" ("charged_transfer_id");
+
+COMMENT ON COLUMN "transfers"."charged_transfer_id" IS 'transfer this fee was charged for';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("charged_transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("fee_schedule_id") REFERENCES "fee_schedules" ("id");
+
+ALTER TABLE "accounts" ADD COLUMN "name" varchar NOT NULL DEFAULT 'Main';
+
+COMMENT ON COLUMN "accounts"."name" IS 'name of the pocket, an owner can hold several accounts in the same currency';
+
+CREATE UNIQUE INDEX "owner_currency_name_key" ON "accounts" ("owner", "currency_id", "name") WHERE "status" <> 'closed';
+
+CREATE TYPE account_member_role AS ENUM (
+  'owner',
+  'full_access',
+  'view_only',
+  'spend_limit'
+);
+
+CREATE TABLE "account_members" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" account_member_role NOT NULL,
+  "spend_limit" bigint,
+  "invited_by" varchar NOT NULL,
+  "accepted_at" timestamptz,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "account_members" ("account_id", "username");
+
+CREATE INDEX ON "account_members" ("username");
+
+COMMENT ON COLUMN "account_members"."spend_limit" IS 'largest amount a spend_limit member can move in a single operation';
+
+COMMENT ON COLUMN "account_members"."accepted_at" IS 'the member has no access until the invitation is accepted';
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("invited_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfers" ADD COLUMN "initiated_by" varchar;
+
+COMMENT ON COLUMN "transfers"."initiated_by" IS 'user who initiated the transfer, only these transfers count towards the spending limits';
+
+CREATE INDEX ON "transfers" ("initiated_by", "createad_at");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
+
+CREATE TABLE "spending_limits" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "currency_id" bigint NOT NULL,
+  "daily_limit" bigint,
+  "monthly_limit" bigint,
+  "createad_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "spending_limits" ("username", "currency_id");
+
+COMMENT ON COLUMN "spending_limits"."daily_limit" IS 'overrides the default of the currency when it is not null, zero means no limit';
+
+COMMENT ON COLUMN "spending_limits"."monthly_limit" IS 'overrides the default of the currency when it is not null, zero means no limit';
+
+ALTER TABLE "spending_limits" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "spending_limits" ADD FOREIGN KEY ("currency_id") REFERENCES "currencies" ("id");
+
+CREATE FUNCTION generate_account_number() RETURNS varchar AS $$
+DECLARE
+  basic varchar := lpad(floor(random() * 1000000000000)::bigint::text, 12, '0');
+BEGIN
+  RETURN 'SB' || lpad((98 - (basic || '281100')::numeric % 97)::text, 2, '0') || basic;
+END;
+$$ LANGUAGE plpgsql VOLATILE;
+
+ALTER TABLE "accounts" ADD COLUMN "account_number" varchar NOT NULL DEFAULT (generate_account_number());
+
+CREATE UNIQUE INDEX ON "accounts" ("account_number");
+
+COMMENT ON COLUMN "accounts"."account_number" IS 'external number of the account with mod 97 check digits, clients use it instead of the id';
+
+ALTER TABLE "currencies" ALTER COLUMN "name" TYPE varchar USING "name"::text;
+
+DROP TYPE currencies;
+
+ALTER TABLE "currencies" RENAME COLUMN "name" TO "code";
+
+ALTER TABLE "currencies"
+  ADD COLUMN "exponent" smallint NOT NULL DEFAULT 2,
+  ADD COLUMN "symbol" varchar NOT NULL DEFAULT '',
+  ADD COLUMN "enabled" boolean NOT NULL DEFAULT true,
+  ADD COLUMN "createad_at" timestamptz NOT NULL DEFAULT (now());
+
+ALTER TABLE "currencies" ADD CONSTRAINT "currencies_code_check" CHECK ("code" ~ '^[A-Z]{3}$');
+
+ALTER TABLE "currencies" ADD CONSTRAINT "currencies_exponent_check" CHECK ("exponent" BETWEEN 0 AND 4);
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 alphabetic code';
+
+COMMENT ON COLUMN "currencies"."exponent" IS 'number of decimals of the minor unit, amounts are stored in minor units';
+
+COMMENT ON COLUMN "currencies"."enabled" IS 'new accounts and transfers are only allowed in enabled currencies';
+
+CREATE INDEX "sessions_username_idx" ON "sessions" ("username", "createad_at");
+
+ALTER TABLE "sessions" ADD COLUMN "family_id" uuid NOT NULL;
+
+ALTER TABLE "sessions" ADD COLUMN "parent_id" uuid;
+
+ALTER TABLE "sessions" ADD COLUMN "rotated_at" timestamptz;
+
+CREATE INDEX ON "sessions" ("family_id");
+
+ALTER TABLE "sessions" ADD FOREIGN KEY ("parent_id") REFERENCES "sessions" ("id");
+
+COMMENT ON COLUMN "sessions"."family_id" IS 'id of the session created at login, every session rotated from it shares it';
+
+COMMENT ON COLUMN "sessions"."parent_id" IS 'session rotated into this one';
+
+COMMENT ON COLUMN "sessions"."rotated_at" IS 'when the refresh token was exchanged, presenting it again revokes the whole family';
+
+CREATE TYPE user_role AS ENUM (
+  'customer',
+  'teller',
+  'admin'
+);
+
+ALTER TABLE "users" ADD COLUMN "role" user_role NOT NULL DEFAULT 'customer';
+
+COMMENT ON COLUMN "users"."role" IS 'tellers can search users and view any account, admins can also freeze accounts, block sessions and manage the catalogs';
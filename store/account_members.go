@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/access"
+)
+
+var (
+	// ErrNotAccountMember is returned when the user is not a member of the account, or has not accepted the invitation yet.
+	ErrNotAccountMember = errors.New("user is not a member of the account")
+	// ErrInvalidAccountMember is returned when inviting a user who is already a member, inviting a spend limit member
+	// without a limit, inviting to a system or closed account, or removing the holder of the account.
+	ErrInvalidAccountMember = errors.New("invalid account member")
+)
+
+// CreateAccountTxParams stores input params of the create account transaction.
+type CreateAccountTxParams struct {
+	Owner      string `json:"owner"`
+	CurrencyID int64  `json:"currency_id"`
+	Name       string `json:"name"`
+}
+
+// CreateAccountTx creates the account and makes its owner the first member of it within a single db transaction.
+func (s *SimpleBankDB) CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (simplebanksql.Account, error) {
+	var account simplebanksql.Account
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		var err error
+		account, err = q.CreateAccount(ctx, simplebanksql.CreateAccountParams{
+			Owner:      arg.Owner,
+			CurrencyID: arg.CurrencyID,
+			Name:       arg.Name,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateAccountMember(ctx, simplebanksql.CreateAccountMemberParams{
+			AccountID:  account.ID,
+			Username:   arg.Owner,
+			Role:       simplebanksql.AccountMemberRoleOwner,
+			InvitedBy:  arg.Owner,
+			AcceptedAt: sql.NullTime{Time: account.CreateadAt, Valid: true},
+		})
+		return err
+	})
+
+	return account, err
+}
+
+// AuthorizeAccountParams stores input params of an account authorization.
+type AuthorizeAccountParams struct {
+	AccountID int64         `json:"account_id"`
+	Username  string        `json:"username"`
+	Action    access.Action `json:"action"`
+	// Amount is checked against the spend limit of the member when the action spends funds.
+	Amount int64 `json:"amount"`
+}
+
+// AuthorizeAccount returns the account once it verifies the user is a member of it allowed to do the action.
+func (s *SimpleBankDB) AuthorizeAccount(ctx context.Context, arg AuthorizeAccountParams) (simplebanksql.Account, error) {
+	account, err := s.GetAccount(ctx, arg.AccountID)
+	if err != nil {
+		return account, err
+	}
+
+	return account, authorizeMember(ctx, s.Queries, arg)
+}
+
+// InviteAccountMemberTxParams stores input params of the invite account member transaction.
+type InviteAccountMemberTxParams struct {
+	AccountID int64                           `json:"account_id"`
+	Username  string                          `json:"username"`
+	Role      simplebanksql.AccountMemberRole `json:"role"`
+	// SpendLimit is required by the spend limit role and ignored by the other roles.
+	SpendLimit int64  `json:"spend_limit"`
+	InvitedBy  string `json:"invited_by"`
+}
+
+// InviteAccountMemberTx invites the user to the account, the user has no access until the invitation is accepted.
+func (s *SimpleBankDB) InviteAccountMemberTx(ctx context.Context, arg InviteAccountMemberTxParams) (simplebanksql.AccountMember, error) {
+	var member simplebanksql.AccountMember
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		// Locking the account serializes the invitation with the account being closed.
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if account.SystemKind.Valid || account.Status == simplebanksql.AccountStatusClosed {
+			return fmt.Errorf("%w: account [%d] does not accept members", ErrInvalidAccountMember, account.ID)
+		}
+
+		memberArg := simplebanksql.CreateAccountMemberParams{
+			AccountID: account.ID,
+			Username:  arg.Username,
+			Role:      arg.Role,
+			InvitedBy: arg.InvitedBy,
+		}
+
+		if arg.Role == simplebanksql.AccountMemberRoleSpendLimit {
+			if arg.SpendLimit <= 0 {
+				return fmt.Errorf("%w: spend_limit members require a positive spend limit", ErrInvalidAccountMember)
+			}
+			memberArg.SpendLimit = sql.NullInt64{Int64: arg.SpendLimit, Valid: true}
+		}
+
+		member, err = q.CreateAccountMember(ctx, memberArg)
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+			return fmt.Errorf("%w: %s was already invited to account [%d]", ErrInvalidAccountMember, arg.Username, account.ID)
+		}
+
+		return err
+	})
+
+	return member, err
+}
+
+// RemoveAccountMemberTx removes the member from the account, or declines the invitation when it is pending.
+// The holder of the account can't be removed.
+func (s *SimpleBankDB) RemoveAccountMemberTx(ctx context.Context, accountID int64, username string) (simplebanksql.AccountMember, error) {
+	var member simplebanksql.AccountMember
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
+		if account.Owner == username {
+			return fmt.Errorf("%w: %s holds account [%d]", ErrInvalidAccountMember, username, account.ID)
+		}
+
+		member, err = q.DeleteAccountMember(ctx, simplebanksql.DeleteAccountMemberParams{
+			AccountID: accountID,
+			Username:  username,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: account [%d]", ErrNotAccountMember, accountID)
+		}
+
+		return err
+	})
+
+	return member, err
+}
+
+// authorizeMember verifies the user accepted the invitation to the account and is allowed to do the action.
+func authorizeMember(ctx context.Context, q *simplebanksql.Queries, arg AuthorizeAccountParams) error {
+	member, err := q.GetAccountMember(ctx, simplebanksql.GetAccountMemberParams{
+		AccountID: arg.AccountID,
+		Username:  arg.Username,
+	})
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !member.AcceptedAt.Valid) {
+		return fmt.Errorf("%w: account [%d]", ErrNotAccountMember, arg.AccountID)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := memberAccess(member).Authorize(arg.Action, arg.Amount); err != nil {
+		return fmt.Errorf("account [%d]: %w", arg.AccountID, err)
+	}
+
+	return nil
+}
+
+// memberAccess returns the access of the member to its account.
+func memberAccess(member simplebanksql.AccountMember) access.Member {
+	return access.Member{
+		Role:       access.Role(member.Role),
+		SpendLimit: member.SpendLimit.Int64,
+	}
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+)
+
+var (
+	// ErrAccountNotActive is returned when a frozen or closed account sends or receives a transfer,
+	// or a dormant account sends one.
+	ErrAccountNotActive = errors.New("account is not active")
+	// ErrInvalidStatusTransition is returned when the account can't move from its status to the requested one,
+	// or the requested transition is reserved to operators.
+	ErrInvalidStatusTransition = errors.New("invalid account status transition")
+	// ErrAccountNotEmpty is returned when closing an account that still has a balance or funds on hold.
+	ErrAccountNotEmpty = errors.New("account balance must be zero to be closed")
+)
+
+// accountStatusTransitions stores the statuses every status can move to, closed is final.
+var accountStatusTransitions = map[simplebanksql.AccountStatus][]simplebanksql.AccountStatus{
+	simplebanksql.AccountStatusActive:  {simplebanksql.AccountStatusFrozen, simplebanksql.AccountStatusDormant, simplebanksql.AccountStatusClosed},
+	simplebanksql.AccountStatusFrozen:  {simplebanksql.AccountStatusActive, simplebanksql.AccountStatusClosed},
+	simplebanksql.AccountStatusDormant: {simplebanksql.AccountStatusActive, simplebanksql.AccountStatusFrozen, simplebanksql.AccountStatusClosed},
+}
+
+// ownerStatusTransitions stores the transitions an owner can make on its own account. An owner can freeze
+// the account, but only an operator can unfreeze it or mark it as dormant.
+var ownerStatusTransitions = map[simplebanksql.AccountStatus][]simplebanksql.AccountStatus{
+	simplebanksql.AccountStatusActive:  {simplebanksql.AccountStatusFrozen, simplebanksql.AccountStatusClosed},
+	simplebanksql.AccountStatusDormant: {simplebanksql.AccountStatusActive, simplebanksql.AccountStatusFrozen, simplebanksql.AccountStatusClosed},
+}
+
+// ChangeAccountStatusTxParams stores input params of the change account status transaction.
+type ChangeAccountStatusTxParams struct {
+	AccountID int64                       `json:"account_id"`
+	Status    simplebanksql.AccountStatus `json:"status"`
+	// ChangedBy is the username of the owner, or the operator name when Operator is set.
+	ChangedBy string `json:"changed_by"`
+	Reason    string `json:"reason"`
+	// Operator allows every transition, otherwise only the transitions of an owner are allowed.
+	Operator bool `json:"operator"`
+}
+
+// ChangeAccountStatusTxResult stores the result of a change account status transaction.
+type ChangeAccountStatusTxResult struct {
+	Account simplebanksql.Account             `json:"account"`
+	Change  simplebanksql.AccountStatusChange `json:"change"`
+}
+
+// ChangeAccountStatusTx moves the account to a new status and records who made the change and why
+// within a single db transaction.
+func (s *SimpleBankDB) ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParams) (ChangeAccountStatusTxResult, error) {
+	var result ChangeAccountStatusTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		// Locking the account serializes the change with transfers in flight.
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkTransition(account, arg); err != nil {
+			return err
+		}
+
+		result, err = changeStatus(ctx, q, account, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// checkTransition verifies the account can move to the requested status, an account is closed only once it is empty.
+func checkTransition(account simplebanksql.Account, arg ChangeAccountStatusTxParams) error {
+	transitions := ownerStatusTransitions
+	if arg.Operator {
+		transitions = accountStatusTransitions
+	}
+
+	if !canTransition(transitions, account.Status, arg.Status) {
+		return fmt.Errorf("%w: account [%d] can't move from %s to %s", ErrInvalidStatusTransition, account.ID, account.Status, arg.Status)
+	}
+
+	if arg.Status == simplebanksql.AccountStatusClosed && (account.Balance != 0 || account.HeldBalance != 0) {
+		return fmt.Errorf("%w: account [%d] has a balance of %d and %d on hold", ErrAccountNotEmpty, account.ID, account.Balance, account.HeldBalance)
+	}
+
+	return nil
+}
+
+// changeStatus updates the status of the account and records the change, the account must be already locked by the caller.
+func changeStatus(ctx context.Context, q *simplebanksql.Queries, account simplebanksql.Account, arg ChangeAccountStatusTxParams) (ChangeAccountStatusTxResult, error) {
+	var result ChangeAccountStatusTxResult
+	var err error
+
+	result.Account, err = q.UpdateAccountStatus(ctx, simplebanksql.UpdateAccountStatusParams{
+		ID:     account.ID,
+		Status: arg.Status,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Change, err = q.CreateAccountStatusChange(ctx, simplebanksql.CreateAccountStatusChangeParams{
+		AccountID:  account.ID,
+		FromStatus: account.Status,
+		ToStatus:   arg.Status,
+		ChangedBy:  arg.ChangedBy,
+		Reason:     arg.Reason,
+	})
+
+	return result, err
+}
+
+func canTransition(transitions map[simplebanksql.AccountStatus][]simplebanksql.AccountStatus, from, to simplebanksql.AccountStatus) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkStatus verifies the from account can send and the to account can receive a transfer.
+// Dormant accounts keep receiving, but they must be reactivated before sending.
+// System accounts only move funds through deposits and withdrawals.
+func checkStatus(fromAccount, toAccount simplebanksql.Account) error {
+	for _, account := range []simplebanksql.Account{fromAccount, toAccount} {
+		if account.SystemKind.Valid {
+			return fmt.Errorf("%w: account [%d] is a system account", ErrAccountNotActive, account.ID)
+		}
+	}
+
+	if fromAccount.Status != simplebanksql.AccountStatusActive {
+		return fmt.Errorf("%w: account [%d] is %s", ErrAccountNotActive, fromAccount.ID, fromAccount.Status)
+	}
+
+	return checkReceiving(toAccount)
+}
+
+// checkReceiving verifies the account can receive a transfer.
+func checkReceiving(account simplebanksql.Account) error {
+	if account.Status == simplebanksql.AccountStatusFrozen || account.Status == simplebanksql.AccountStatusClosed {
+		return fmt.Errorf("%w: account [%d] is %s", ErrAccountNotActive, account.ID, account.Status)
+	}
+
+	return nil
+}
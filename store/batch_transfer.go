@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+)
+
+// ErrInvalidBatchLeg is returned when a leg of a batch transfer credits the from account itself,
+// or its amount is not positive.
+var ErrInvalidBatchLeg = errors.New("invalid batch transfer leg")
+
+// BatchTransferLeg stores the account credited by a leg of a batch transfer and the amount debited for it.
+type BatchTransferLeg struct {
+	ToAccountID int64 `json:"to_account_id"`
+	Amount      int64 `json:"amount"`
+}
+
+// BatchTransferTxParams stores input params of the batch transfer transaction.
+// Every leg is debited from the from account in its own currency.
+type BatchTransferTxParams struct {
+	FromAccountID int64              `json:"from_account_id"`
+	Legs          []BatchTransferLeg `json:"legs"`
+	// InitiatedBy is the user who initiated the batch, the spending limits are evaluated against its total amount.
+	InitiatedBy string `json:"-"`
+}
+
+// BatchTransferTxResult stores the result of a batch transfer transaction.
+type BatchTransferTxResult struct {
+	// FromAccount is the from account once every leg is posted.
+	FromAccount simplebanksql.Account `json:"from_account"`
+	TotalAmount int64                 `json:"total_amount"`
+	// TotalFees is charged on top of the total amount, every leg itemizes its own fee.
+	TotalFees int64 `json:"total_fees"`
+	// Legs stores the result of every leg in the order they were requested.
+	Legs []TransferTxResult `json:"legs"`
+}
+
+// BatchTransferTx performs every leg of a batch transfer within a single db transaction, either all legs
+// are posted or none of them. The total amount plus the fees is checked against the from account before any leg is posted.
+func (s *SimpleBankDB) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error) {
+	var result BatchTransferTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		total, err := batchTotal(arg)
+		if err != nil {
+			return err
+		}
+
+		ids := []int64{arg.FromAccountID}
+		for _, leg := range arg.Legs {
+			ids = append(ids, leg.ToAccountID)
+		}
+
+		accounts, err := lockAccountsInOrder(ctx, q, ids)
+		if err != nil {
+			return err
+		}
+
+		fromAccount := accounts[arg.FromAccountID]
+		for i, leg := range arg.Legs {
+			if err := checkStatus(fromAccount, accounts[leg.ToAccountID]); err != nil {
+				return fmt.Errorf("leg %d: %w", i, err)
+			}
+		}
+
+		schedules := make([]*simplebanksql.FeeSchedule, len(arg.Legs))
+		fees := make([]int64, len(arg.Legs))
+		var totalFees int64
+		for i, leg := range arg.Legs {
+			schedules[i], fees[i], err = evaluateFee(ctx, q, fromAccount, leg.Amount)
+			if err != nil {
+				return fmt.Errorf("leg %d: %w", i, err)
+			}
+			totalFees += fees[i]
+		}
+
+		if err := checkFunds(fromAccount, total+totalFees); err != nil {
+			return err
+		}
+
+		if err := s.checkSpendingLimits(ctx, q, fromAccount, arg.InitiatedBy, total); err != nil {
+			return err
+		}
+
+		result = BatchTransferTxResult{
+			FromAccount: fromAccount,
+			TotalAmount: total,
+			TotalFees:   totalFees,
+			Legs:        make([]TransferTxResult, 0, len(arg.Legs)),
+		}
+
+		for i, leg := range arg.Legs {
+			toAmount, exchangeRate, err := convertAmount(ctx, q, fromAccount, accounts[leg.ToAccountID], leg.Amount)
+			if err != nil {
+				return fmt.Errorf("leg %d: %w", i, err)
+			}
+
+			transferArg := simplebanksql.CreateTransferParams{
+				FromAccountID: arg.FromAccountID,
+				ToAccountID:   leg.ToAccountID,
+				Amount:        leg.Amount,
+				ToAmount:      toAmount,
+				ExchangeRate:  sameCurrencyRate,
+				Status:        simplebanksql.TransferStatusPosted,
+				InitiatedBy:   sql.NullString{String: arg.InitiatedBy, Valid: arg.InitiatedBy != ""},
+			}
+
+			if exchangeRate != nil {
+				transferArg.ExchangeRate = exchangeRate.Rate
+				transferArg.ExchangeRateID = sql.NullInt64{Int64: exchangeRate.ID, Valid: true}
+			}
+
+			legResult, err := postTransfer(ctx, q, transferArg)
+			if err != nil {
+				return fmt.Errorf("leg %d: %w", i, err)
+			}
+
+			legResult.ExchangeRate = exchangeRate
+			if schedules[i] != nil {
+				legResult.Fee, legResult.FromAccount, err = chargeFee(ctx, q, legResult.Transfer, fromAccount.CurrencyID, schedules[i], fees[i])
+				if err != nil {
+					return fmt.Errorf("leg %d: %w", i, err)
+				}
+			}
+
+			result.Legs = append(result.Legs, legResult)
+			result.FromAccount = legResult.FromAccount
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// batchTotal validates the legs of the batch and returns the total amount debited from the from account.
+func batchTotal(arg BatchTransferTxParams) (int64, error) {
+	var total int64
+	for i, leg := range arg.Legs {
+		if leg.ToAccountID == arg.FromAccountID {
+			return 0, fmt.Errorf("%w: leg %d credits the from account [%d]", ErrInvalidBatchLeg, i, arg.FromAccountID)
+		}
+
+		if leg.Amount <= 0 {
+			return 0, fmt.Errorf("%w: leg %d amount must be positive", ErrInvalidBatchLeg, i)
+		}
+
+		if total > math.MaxInt64-leg.Amount {
+			return 0, fmt.Errorf("%w: total amount overflows", ErrInvalidBatchLeg)
+		}
+		total += leg.Amount
+	}
+
+	return total, nil
+}
+
+// lockAccountsInOrder locks every distinct account for update in ascending id order, so batches
+// sharing accounts with other transfers can't deadlock.
+func lockAccountsInOrder(ctx context.Context, q *simplebanksql.Queries, ids []int64) (map[int64]simplebanksql.Account, error) {
+	sorted := make([]int64, len(ids))
+	copy(sorted, ids)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	accounts := make(map[int64]simplebanksql.Account, len(sorted))
+	for _, id := range sorted {
+		if _, locked := accounts[id]; locked {
+			continue
+		}
+
+		account, err := q.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("account [%d]: %w", id, err)
+		}
+		accounts[id] = account
+	}
+
+	return accounts, nil
+}
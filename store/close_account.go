@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+)
+
+// ErrInvalidSweepAccount is returned when an account with a balance is closed without a sweep account,
+// or the sweep account is the closed account itself or belongs to another owner.
+var ErrInvalidSweepAccount = errors.New("invalid sweep account")
+
+// CloseAccountTxParams stores input params of the close account transaction.
+type CloseAccountTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// SweepAccountID receives the remaining balance, it is only required when the balance is not zero.
+	SweepAccountID int64  `json:"sweep_account_id"`
+	ClosedBy       string `json:"closed_by"`
+	Reason         string `json:"reason"`
+	// Operator allows closing frozen accounts.
+	Operator bool `json:"operator"`
+}
+
+// CloseAccountTxResult stores the result of a close account transaction.
+type CloseAccountTxResult struct {
+	ChangeAccountStatusTxResult
+	// Sweep is the transfer that moved the remaining balance, it is nil when the balance was zero.
+	Sweep *TransferTxResult `json:"sweep,omitempty"`
+}
+
+// CloseAccountTx moves the remaining balance of the account to another account of the same owner,
+// and marks the account as closed within a single db transaction. Entries and transfers of the account are kept.
+func (s *SimpleBankDB) CloseAccountTx(ctx context.Context, arg CloseAccountTxParams) (CloseAccountTxResult, error) {
+	var result CloseAccountTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		if arg.SweepAccountID == arg.AccountID {
+			return fmt.Errorf("%w: account [%d] can't be swept into itself", ErrInvalidSweepAccount, arg.AccountID)
+		}
+
+		var account, sweepAccount simplebanksql.Account
+		var err error
+		if arg.SweepAccountID != 0 {
+			account, sweepAccount, err = lockAccounts(ctx, q, arg.AccountID, arg.SweepAccountID)
+		} else {
+			account, err = q.GetAccountForUpdate(ctx, arg.AccountID)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		statusArg := ChangeAccountStatusTxParams{
+			AccountID: account.ID,
+			Status:    simplebanksql.AccountStatusClosed,
+			ChangedBy: arg.ClosedBy,
+			Reason:    arg.Reason,
+			Operator:  arg.Operator,
+		}
+
+		// The balance is checked once it is swept.
+		swept := account
+		swept.Balance = 0
+		if err := checkTransition(swept, statusArg); err != nil {
+			return err
+		}
+
+		if account.Balance < 0 {
+			return fmt.Errorf("%w: account [%d] is overdrawn by %d", ErrAccountNotEmpty, account.ID, -account.Balance)
+		}
+
+		if account.Balance > 0 {
+			result.Sweep, err = sweep(ctx, q, account, sweepAccount)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.ChangeAccountStatusTxResult, err = changeStatus(ctx, q, account, statusArg)
+		return err
+	})
+
+	return result, err
+}
+
+// sweep transfers the whole balance of the account to the sweep account of the same owner.
+// The account is not required to be active, so frozen accounts can be closed as well.
+func sweep(ctx context.Context, q *simplebanksql.Queries, account, sweepAccount simplebanksql.Account) (*TransferTxResult, error) {
+	if sweepAccount.ID == 0 {
+		return nil, fmt.Errorf("%w: account [%d] has a balance of %d", ErrInvalidSweepAccount, account.ID, account.Balance)
+	}
+
+	if sweepAccount.Owner != account.Owner {
+		return nil, fmt.Errorf("%w: account [%d] belongs to another owner", ErrInvalidSweepAccount, sweepAccount.ID)
+	}
+
+	if err := checkReceiving(sweepAccount); err != nil {
+		return nil, err
+	}
+
+	toAmount, exchangeRate, err := convertAmount(ctx, q, account, sweepAccount, account.Balance)
+	if err != nil {
+		return nil, err
+	}
+
+	transferArg := simplebanksql.CreateTransferParams{
+		FromAccountID: account.ID,
+		ToAccountID:   sweepAccount.ID,
+		Amount:        account.Balance,
+		ToAmount:      toAmount,
+		ExchangeRate:  sameCurrencyRate,
+		Status:        simplebanksql.TransferStatusPosted,
+	}
+
+	if exchangeRate != nil {
+		transferArg.ExchangeRate = exchangeRate.Rate
+		transferArg.ExchangeRateID = sql.NullInt64{Int64: exchangeRate.ID, Valid: true}
+	}
+
+	result, err := postTransfer(ctx, q, transferArg)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ExchangeRate = exchangeRate
+	return &result, nil
+}
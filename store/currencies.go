@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+)
+
+// currencyCatalogDuration is how long the currency catalog is cached before it is loaded again.
+const currencyCatalogDuration = time.Minute
+
+var (
+	// ErrCurrencyNotFound is returned when a currency is not part of the catalog.
+	ErrCurrencyNotFound = errors.New("currency not found")
+	// ErrCurrencyDisabled is returned when a currency of the catalog is disabled.
+	ErrCurrencyDisabled = errors.New("currency is disabled")
+)
+
+// currencyCatalog caches the currencies so validating the currency of a request doesn't hit the database.
+type currencyCatalog struct {
+	mu       sync.RWMutex
+	loadedAt time.Time
+	byID     map[int64]simplebanksql.Currency
+	byCode   map[string]simplebanksql.Currency
+}
+
+// invalidate drops the cached currencies, they are loaded again on the next lookup.
+func (c *currencyCatalog) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loadedAt = time.Time{}
+}
+
+// load returns the cached currencies, loading them when they have expired.
+func (c *currencyCatalog) load(ctx context.Context, q *simplebanksql.Queries) (map[int64]simplebanksql.Currency, map[string]simplebanksql.Currency, error) {
+	c.mu.RLock()
+	if time.Since(c.loadedAt) < currencyCatalogDuration {
+		defer c.mu.RUnlock()
+		return c.byID, c.byCode, nil
+	}
+	c.mu.RUnlock()
+
+	currencies, err := q.ListCurrencies(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[int64]simplebanksql.Currency, len(currencies))
+	byCode := make(map[string]simplebanksql.Currency, len(currencies))
+	for _, currency := range currencies {
+		byID[currency.ID] = currency
+		byCode[currency.Code] = currency
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.byID, c.byCode, c.loadedAt = byID, byCode, time.Now()
+	return byID, byCode, nil
+}
+
+// EnabledCurrencyParams identifies a currency by its code or by its id, the code is used when both are set
+// and they must be the same currency.
+type EnabledCurrencyParams struct {
+	ID   int64
+	Code string
+}
+
+// EnabledCurrency looks up an enabled currency of the catalog, the catalog is cached for a minute.
+func (s *SimpleBankDB) EnabledCurrency(ctx context.Context, arg EnabledCurrencyParams) (simplebanksql.Currency, error) {
+	byID, byCode, err := s.currencies.load(ctx, s.Queries)
+	if err != nil {
+		return simplebanksql.Currency{}, err
+	}
+
+	var (
+		currency simplebanksql.Currency
+		ok       bool
+	)
+	if arg.Code != "" {
+		code := strings.ToUpper(arg.Code)
+		if currency, ok = byCode[code]; !ok {
+			return currency, fmt.Errorf("%w: %s", ErrCurrencyNotFound, code)
+		}
+
+		if arg.ID != 0 && arg.ID != currency.ID {
+			return currency, fmt.Errorf("%w: %s is not the currency [%d]", ErrCurrencyNotFound, code, arg.ID)
+		}
+	} else if currency, ok = byID[arg.ID]; !ok {
+		return currency, fmt.Errorf("%w: [%d]", ErrCurrencyNotFound, arg.ID)
+	}
+
+	if !currency.Enabled {
+		return currency, fmt.Errorf("%w: %s", ErrCurrencyDisabled, currency.Code)
+	}
+
+	return currency, nil
+}
+
+// CreateCurrencyTxResult stores the result of adding a currency to the catalog.
+type CreateCurrencyTxResult struct {
+	Currency       simplebanksql.Currency  `json:"currency"`
+	SystemAccounts []simplebanksql.Account `json:"system_accounts"`
+}
+
+// CreateCurrencyTx adds a currency to the catalog along with its system accounts, so deposits, withdrawals,
+// interest and fees can be posted in it.
+func (s *SimpleBankDB) CreateCurrencyTx(ctx context.Context, arg simplebanksql.CreateCurrencyParams) (CreateCurrencyTxResult, error) {
+	var result CreateCurrencyTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		var err error
+		arg.Code = strings.ToUpper(arg.Code)
+		result.Currency, err = q.CreateCurrency(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		result.SystemAccounts, err = q.CreateSystemAccounts(ctx, result.Currency.ID)
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+
+	s.currencies.invalidate()
+	return result, nil
+}
+
+// UpdateCurrency updates the symbol or enables and disables a currency of the catalog.
+func (s *SimpleBankDB) UpdateCurrency(ctx context.Context, arg simplebanksql.UpdateCurrencyParams) (simplebanksql.Currency, error) {
+	currency, err := s.Queries.UpdateCurrency(ctx, arg)
+	if err != nil {
+		return currency, err
+	}
+
+	s.currencies.invalidate()
+	return currency, nil
+}
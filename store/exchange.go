@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/money"
+)
+
+// sameCurrencyRate is the exchange rate recorded on transfers between accounts of the same currency.
+const sameCurrencyRate = "1"
+
+// ErrExchangeRateNotFound is returned when there is no rate in effect between two currencies.
+var ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+// convertAmount converts amount from the currency of fromAccount to the currency of toAccount
+// by using the exchange rate in effect. The returned rate is nil for accounts of the same currency.
+func convertAmount(ctx context.Context, q *simplebanksql.Queries, fromAccount, toAccount simplebanksql.Account, amount int64) (int64, *simplebanksql.ExchangeRate, error) {
+	if fromAccount.CurrencyID == toAccount.CurrencyID {
+		return amount, nil, nil
+	}
+
+	rate, err := q.GetExchangeRate(ctx, simplebanksql.GetExchangeRateParams{
+		FromCurrencyID: fromAccount.CurrencyID,
+		ToCurrencyID:   toAccount.CurrencyID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil, ErrExchangeRateNotFound
+	}
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	converted, err := money.ApplyRate(amount, rate.Rate)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return converted, &rate, nil
+}
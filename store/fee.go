@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/money"
+)
+
+// TransferFee stores the fee charged for a transfer. The fee is posted as its own transfer
+// from the from account to the fee revenue system account of its currency.
+type TransferFee struct {
+	ScheduleID int64                  `json:"schedule_id"`
+	Amount     int64                  `json:"amount"`
+	Transfer   simplebanksql.Transfer `json:"transfer"`
+	// Entry is the debit of the fee on the from account.
+	Entry simplebanksql.Entry `json:"entry"`
+}
+
+// evaluateFee returns the fee schedule of the currency and tier of fromAccount, and the fee of amount.
+// The schedule is nil when no fee is charged.
+func evaluateFee(ctx context.Context, q *simplebanksql.Queries, fromAccount simplebanksql.Account, amount int64) (*simplebanksql.FeeSchedule, int64, error) {
+	schedule, err := q.GetFeeSchedule(ctx, simplebanksql.GetFeeScheduleParams{
+		CurrencyID:  fromAccount.CurrencyID,
+		AccountTier: fromAccount.Tier,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, nil
+	}
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fee, err := money.FeeRule{
+		Flat:       schedule.FlatAmount,
+		Percentage: schedule.Percentage,
+		Min:        schedule.MinFee,
+		Max:        schedule.MaxFee.Int64,
+	}.Apply(amount)
+	if err != nil || fee == 0 {
+		return nil, 0, err
+	}
+
+	return &schedule, fee, nil
+}
+
+// chargeFee posts the fee of the transfer and returns the from account once the fee is debited.
+// The from account must be already locked by the caller. The fee revenue account is always locked
+// last, and it is never locked first by a transfer, so it can't deadlock with other transfers.
+func chargeFee(ctx context.Context, q *simplebanksql.Queries, transfer simplebanksql.Transfer, currencyID int64, schedule *simplebanksql.FeeSchedule, fee int64) (*TransferFee, simplebanksql.Account, error) {
+	systemAccount, err := q.GetSystemAccount(ctx, simplebanksql.GetSystemAccountParams{
+		SystemKind: simplebanksql.NullSystemAccountKind{SystemAccountKind: simplebanksql.SystemAccountKindFeeRevenue, Valid: true},
+		CurrencyID: currencyID,
+	})
+	if err != nil {
+		return nil, simplebanksql.Account{}, fmt.Errorf("unable to get fee_revenue system account of currency [%d]: %w", currencyID, err)
+	}
+
+	result, err := postTransfer(ctx, q, simplebanksql.CreateTransferParams{
+		FromAccountID:     transfer.FromAccountID,
+		ToAccountID:       systemAccount.ID,
+		Amount:            fee,
+		ToAmount:          fee,
+		ExchangeRate:      sameCurrencyRate,
+		Status:            simplebanksql.TransferStatusPosted,
+		ChargedTransferID: sql.NullInt64{Int64: transfer.ID, Valid: true},
+		FeeScheduleID:     sql.NullInt64{Int64: schedule.ID, Valid: true},
+	})
+	if err != nil {
+		return nil, simplebanksql.Account{}, err
+	}
+
+	return &TransferFee{
+		ScheduleID: schedule.ID,
+		Amount:     fee,
+		Transfer:   result.Transfer,
+		Entry:      result.FromEntry,
+	}, result.FromAccount, nil
+}
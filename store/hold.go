@@ -0,0 +1,233 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/money"
+)
+
+var (
+	// ErrTransferNotPending is returned when capturing or voiding a transfer that was not authorized,
+	// or that was already captured, voided, or expired.
+	ErrTransferNotPending = errors.New("transfer is not pending")
+	// ErrHoldExpired is returned when capturing a transfer whose hold has expired.
+	ErrHoldExpired = errors.New("hold has expired")
+	// ErrCaptureExceedsHold is returned when capturing more than the held amount.
+	ErrCaptureExceedsHold = errors.New("capture exceeds the held amount")
+)
+
+// CaptureTransferTxParams stores input params of the capture transfer transaction.
+type CaptureTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount to capture in the currency of the from account, zero captures the full held amount.
+	Amount int64 `json:"amount"`
+}
+
+// ReleaseHoldTxResult stores the result of voiding or expiring a pending transfer.
+type ReleaseHoldTxResult struct {
+	Transfer    simplebanksql.Transfer `json:"transfer"`
+	FromAccount simplebanksql.Account  `json:"from_account"`
+	Hold        simplebanksql.Hold     `json:"hold"`
+}
+
+// CaptureTransferTx settles a pending transfer within a single db transaction. The hold is released,
+// and the entries and balances are posted for the captured amount. A partial capture releases the rest of the hold.
+func (s *SimpleBankDB) CaptureTransferTx(ctx context.Context, arg CaptureTransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		pending, hold, err := lockPendingTransfer(ctx, q, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if !time.Now().Before(hold.ExpiresAt) {
+			return fmt.Errorf("%w: hold of transfer [%d] expired at %v", ErrHoldExpired, pending.ID, hold.ExpiresAt)
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+
+		if amount < 0 || amount > hold.Amount {
+			return fmt.Errorf("%w: %d requested, %d held", ErrCaptureExceedsHold, amount, hold.Amount)
+		}
+
+		fromAccount, toAccount, err := lockAccounts(ctx, q, pending.FromAccountID, pending.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkStatus(fromAccount, toAccount); err != nil {
+			return err
+		}
+
+		released, _, err := releaseHold(ctx, q, hold)
+		if err != nil {
+			return err
+		}
+
+		// The amount is converted with the rate of the authorization.
+		toAmount := pending.ToAmount
+		if amount != hold.Amount {
+			toAmount, err = money.ApplyRate(amount, pending.ExchangeRate)
+			if err != nil {
+				return err
+			}
+		}
+
+		captured, err := q.CaptureTransfer(ctx, simplebanksql.CaptureTransferParams{
+			ID:       pending.ID,
+			Amount:   amount,
+			ToAmount: toAmount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err = postEntries(ctx, q, captured)
+		if err != nil {
+			return err
+		}
+
+		result.Hold = &released
+
+		// The fee of an authorized transfer is charged on the captured amount.
+		schedule, fee, err := evaluateFee(ctx, q, result.FromAccount, amount)
+		if err != nil || schedule == nil {
+			return err
+		}
+
+		if err := checkFunds(result.FromAccount, fee); err != nil {
+			return err
+		}
+
+		result.Fee, result.FromAccount, err = chargeFee(ctx, q, captured, fromAccount.CurrencyID, schedule, fee)
+		return err
+	})
+
+	return result, err
+}
+
+// VoidTransferTx cancels a pending transfer and releases its hold, no entries are posted.
+func (s *SimpleBankDB) VoidTransferTx(ctx context.Context, transferID int64) (ReleaseHoldTxResult, error) {
+	var result ReleaseHoldTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		pending, hold, err := lockPendingTransfer(ctx, q, transferID)
+		if err != nil {
+			return err
+		}
+
+		result, err = releasePendingTransfer(ctx, q, pending, hold, simplebanksql.TransferStatusVoided)
+		return err
+	})
+
+	return result, err
+}
+
+// ExpireTransferTx releases the hold of a pending transfer once it has expired.
+func (s *SimpleBankDB) ExpireTransferTx(ctx context.Context, transferID int64) (ReleaseHoldTxResult, error) {
+	var result ReleaseHoldTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		pending, hold, err := lockPendingTransfer(ctx, q, transferID)
+		if err != nil {
+			return err
+		}
+
+		if time.Now().Before(hold.ExpiresAt) {
+			return fmt.Errorf("hold of transfer [%d] expires at %v", pending.ID, hold.ExpiresAt)
+		}
+
+		result, err = releasePendingTransfer(ctx, q, pending, hold, simplebanksql.TransferStatusExpired)
+		return err
+	})
+
+	return result, err
+}
+
+// holdTransfer creates a pending transfer and holds its amount on the from account.
+// Accounts must be already locked by the caller.
+func holdTransfer(ctx context.Context, q *simplebanksql.Queries, arg simplebanksql.CreateTransferParams, toAccount simplebanksql.Account, duration time.Duration) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
+
+	result.Transfer, err = q.CreateTransfer(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+
+	hold, err := q.CreateHold(ctx, simplebanksql.CreateHoldParams{
+		TransferID: result.Transfer.ID,
+		AccountID:  arg.FromAccountID,
+		Amount:     arg.Amount,
+		ExpiresAt:  time.Now().Add(duration),
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.FromAccount, err = q.AddAccountHeldBalance(ctx, simplebanksql.AddAccountHeldBalanceParams{
+		ID:     arg.FromAccountID,
+		Amount: arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Hold = &hold
+	result.ToAccount = toAccount
+	return result, nil
+}
+
+// lockPendingTransfer locks the transfer for update and verifies it is still pending.
+// Locking the transfer serializes concurrent captures and voids of the same hold.
+func lockPendingTransfer(ctx context.Context, q *simplebanksql.Queries, transferID int64) (simplebanksql.Transfer, simplebanksql.Hold, error) {
+	transfer, err := q.GetTransferForUpdate(ctx, transferID)
+	if err != nil {
+		return transfer, simplebanksql.Hold{}, err
+	}
+
+	if transfer.Status != simplebanksql.TransferStatusPending {
+		return transfer, simplebanksql.Hold{}, fmt.Errorf("%w: transfer [%d] is %s", ErrTransferNotPending, transfer.ID, transfer.Status)
+	}
+
+	hold, err := q.GetHoldByTransfer(ctx, transfer.ID)
+	return transfer, hold, err
+}
+
+// releasePendingTransfer releases the hold and moves the pending transfer to status.
+func releasePendingTransfer(ctx context.Context, q *simplebanksql.Queries, pending simplebanksql.Transfer, hold simplebanksql.Hold, status simplebanksql.TransferStatus) (ReleaseHoldTxResult, error) {
+	var result ReleaseHoldTxResult
+	var err error
+
+	result.Hold, result.FromAccount, err = releaseHold(ctx, q, hold)
+	if err != nil {
+		return result, err
+	}
+
+	result.Transfer, err = q.UpdateTransferStatus(ctx, simplebanksql.UpdateTransferStatusParams{
+		ID:     pending.ID,
+		Status: status,
+	})
+
+	return result, err
+}
+
+// releaseHold marks the hold as released and gives its amount back to the available balance of the account.
+func releaseHold(ctx context.Context, q *simplebanksql.Queries, hold simplebanksql.Hold) (simplebanksql.Hold, simplebanksql.Account, error) {
+	released, err := q.ReleaseHold(ctx, hold.ID)
+	if err != nil {
+		return released, simplebanksql.Account{}, err
+	}
+
+	account, err := q.AddAccountHeldBalance(ctx, simplebanksql.AddAccountHeldBalanceParams{
+		ID:     hold.AccountID,
+		Amount: -hold.Amount,
+	})
+
+	return released, account, err
+}
@@ -0,0 +1,235 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/money"
+)
+
+// interestAccountsPage is the number of accounts accrued on each page of a run.
+const interestAccountsPage = 100
+
+// accrualDecimals is the precision of the daily accruals in minor units.
+const accrualDecimals = 10
+
+// CreateInterestPlanTxParams stores input params of the create interest plan transaction.
+type CreateInterestPlanTxParams struct {
+	Name  string       `json:"name"`
+	Tiers []money.Tier `json:"tiers"`
+}
+
+// CreateInterestPlanTxResult stores the result of a create interest plan transaction.
+type CreateInterestPlanTxResult struct {
+	Plan  simplebanksql.InterestPlan       `json:"plan"`
+	Tiers []simplebanksql.InterestPlanTier `json:"tiers"`
+}
+
+// CreateInterestPlanTx creates an interest plan with its tiers within a single db transaction.
+func (s *SimpleBankDB) CreateInterestPlanTx(ctx context.Context, arg CreateInterestPlanTxParams) (CreateInterestPlanTxResult, error) {
+	var result CreateInterestPlanTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		var err error
+		result.Plan, err = q.CreateInterestPlan(ctx, arg.Name)
+		if err != nil {
+			return err
+		}
+
+		for _, tier := range arg.Tiers {
+			planTier, err := q.CreateInterestPlanTier(ctx, simplebanksql.CreateInterestPlanTierParams{
+				PlanID:     result.Plan.ID,
+				MinBalance: tier.MinBalance,
+				AnnualRate: tier.AnnualRate,
+			})
+			if err != nil {
+				return err
+			}
+			result.Tiers = append(result.Tiers, planTier)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// AccrueInterestTxResult stores the result of an accrue interest transaction.
+type AccrueInterestTxResult struct {
+	Day time.Time `json:"day"`
+	// Accrued is the number of accounts accrued, accounts already accrued for the day are not counted.
+	Accrued int `json:"accrued"`
+}
+
+// AccrueInterestTx records the interest earned on day by every account attached to an interest plan,
+// using the balance at the end of the day. Accruing a day twice does not create new accruals.
+func (s *SimpleBankDB) AccrueInterestTx(ctx context.Context, day time.Time) (AccrueInterestTxResult, error) {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	result := AccrueInterestTxResult{Day: day}
+
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		tiers := make(map[int64][]money.Tier)
+		var afterID int64
+		for {
+			accounts, err := q.ListInterestBearingAccounts(ctx, simplebanksql.ListInterestBearingAccountsParams{
+				AfterID:   afterID,
+				PageLimit: interestAccountsPage,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, account := range accounts {
+				accrued, err := accrueInterest(ctx, q, account, day, tiers)
+				if err != nil {
+					return fmt.Errorf("account [%d]: %w", account.ID, err)
+				}
+
+				if accrued {
+					result.Accrued++
+				}
+			}
+
+			if len(accounts) < interestAccountsPage {
+				return nil
+			}
+			afterID = accounts[len(accounts)-1].ID
+		}
+	})
+
+	return result, err
+}
+
+// accrueInterest records the interest of the account for day, tiers caches the tiers of every plan.
+func accrueInterest(ctx context.Context, q *simplebanksql.Queries, account simplebanksql.Account, day time.Time, tiers map[int64][]money.Tier) (bool, error) {
+	planID := account.InterestPlanID.Int64
+	if _, ok := tiers[planID]; !ok {
+		planTiers, err := q.ListInterestPlanTiers(ctx, planID)
+		if err != nil {
+			return false, err
+		}
+
+		for _, tier := range planTiers {
+			tiers[planID] = append(tiers[planID], money.Tier{MinBalance: tier.MinBalance, AnnualRate: tier.AnnualRate})
+		}
+	}
+
+	balance, err := q.GetAccountBalanceAt(ctx, simplebanksql.GetAccountBalanceAtParams{
+		AccountID: account.ID,
+		At:        day.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	interest, err := money.DailyInterest(balance, tiers[planID], money.DaysInYear(day.Year()))
+	if err != nil {
+		return false, err
+	}
+
+	if interest.Sign() == 0 {
+		return false, nil
+	}
+
+	_, err = q.CreateInterestAccrual(ctx, simplebanksql.CreateInterestAccrualParams{
+		AccountID:   account.ID,
+		PlanID:      planID,
+		AccrualDate: day,
+		Balance:     balance,
+		Amount:      interest.FloatString(accrualDecimals),
+	})
+	if errors.Is(err, sql.ErrNoRows) { // already accrued.
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// CapitalizeInterestTxParams stores input params of the capitalize interest transaction.
+type CapitalizeInterestTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Before capitalizes the accruals of the days before it, usually the first day of the month.
+	Before time.Time `json:"before"`
+}
+
+// CapitalizeInterestTxResult stores the result of a capitalize interest transaction.
+type CapitalizeInterestTxResult struct {
+	Amount   int64 `json:"amount"`
+	Accruals int64 `json:"accruals"`
+	// Transfer is nil when the accrued interest rounds to zero, the accruals are kept for the next capitalization.
+	Transfer *TransferTxResult `json:"transfer,omitempty"`
+}
+
+// CapitalizeInterestTx posts the pending accruals of the account as a single transfer from the
+// interest expense system account of its currency. The sum of the accruals is rounded once.
+func (s *SimpleBankDB) CapitalizeInterestTx(ctx context.Context, arg CapitalizeInterestTxParams) (CapitalizeInterestTxResult, error) {
+	var result CapitalizeInterestTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		systemAccount, err := q.GetSystemAccount(ctx, simplebanksql.GetSystemAccountParams{
+			SystemKind: simplebanksql.NullSystemAccountKind{SystemAccountKind: simplebanksql.SystemAccountKindInterestExpense, Valid: true},
+			CurrencyID: account.CurrencyID,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get interest_expense system account of currency [%d]: %w", account.CurrencyID, err)
+		}
+
+		// Locking the account serializes concurrent capitalizations of the same accruals.
+		systemAccount, account, err = lockAccounts(ctx, q, systemAccount.ID, account.ID)
+		if err != nil {
+			return err
+		}
+
+		// Interest is still paid to frozen accounts, it is not movement initiated by the owner.
+		if account.Status == simplebanksql.AccountStatusClosed {
+			return fmt.Errorf("%w: account [%d] is %s", ErrAccountNotActive, account.ID, account.Status)
+		}
+
+		pending, err := q.SumPendingAccruals(ctx, simplebanksql.SumPendingAccrualsParams{
+			AccountID: account.ID,
+			Before:    arg.Before,
+		})
+		if err != nil {
+			return err
+		}
+
+		total, err := money.ParseRate(pending.Amount)
+		if err != nil {
+			return err
+		}
+
+		result.Accruals = pending.Accruals
+		result.Amount, err = money.Round(total)
+		if err != nil || result.Amount <= 0 {
+			return err
+		}
+
+		transfer, err := postTransfer(ctx, q, simplebanksql.CreateTransferParams{
+			FromAccountID: systemAccount.ID,
+			ToAccountID:   account.ID,
+			Amount:        result.Amount,
+			ToAmount:      result.Amount,
+			ExchangeRate:  sameCurrencyRate,
+			Status:        simplebanksql.TransferStatusPosted,
+		})
+		if err != nil {
+			return err
+		}
+		result.Transfer = &transfer
+
+		return q.CapitalizeAccruals(ctx, simplebanksql.CapitalizeAccrualsParams{
+			TransferID: sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true},
+			AccountID:  account.ID,
+			Before:     arg.Before,
+		})
+	})
+
+	return result, err
+}
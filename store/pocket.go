@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+)
+
+// ErrInvalidPocketMove is returned when funds are moved into the same account, or between accounts
+// of different owners or currencies.
+var ErrInvalidPocketMove = errors.New("invalid pocket move")
+
+// MovePocketFundsTxParams stores input params of the move pocket funds transaction.
+type MovePocketFundsTxParams struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	Amount        int64 `json:"amount"`
+}
+
+// MovePocketFundsTx moves funds between two pockets of the same owner and currency within a single db transaction.
+// The move is posted instantly, no exchange rate nor fee applies to it.
+func (s *SimpleBankDB) MovePocketFundsTx(ctx context.Context, arg MovePocketFundsTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		if arg.FromAccountID == arg.ToAccountID {
+			return fmt.Errorf("%w: account [%d] can't move funds into itself", ErrInvalidPocketMove, arg.FromAccountID)
+		}
+
+		fromAccount, toAccount, err := lockAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		if fromAccount.Owner != toAccount.Owner || fromAccount.CurrencyID != toAccount.CurrencyID {
+			return fmt.Errorf("%w: accounts [%d] and [%d] are not pockets of the same owner and currency", ErrInvalidPocketMove, fromAccount.ID, toAccount.ID)
+		}
+
+		if err := checkStatus(fromAccount, toAccount); err != nil {
+			return err
+		}
+
+		if err := checkFunds(fromAccount, arg.Amount); err != nil {
+			return err
+		}
+
+		result, err = postTransfer(ctx, q, simplebanksql.CreateTransferParams{
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        arg.Amount,
+			ToAmount:      arg.Amount,
+			ExchangeRate:  sameCurrencyRate,
+			Status:        simplebanksql.TransferStatusPosted,
+		})
+		return err
+	})
+
+	return result, err
+}
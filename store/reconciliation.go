@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+)
+
+// ReconciliationDetails stores the inconsistencies found between the balances, entries, and transfers.
+type ReconciliationDetails struct {
+	// DriftedAccounts have a balance different than the sum of their entries.
+	DriftedAccounts []simplebanksql.ListDriftedAccountsRow `json:"drifted_accounts"`
+	// OrphanedEntries do not belong to a posted transfer.
+	OrphanedEntries []simplebanksql.Entry `json:"orphaned_entries"`
+	// UnbalancedTransfers are posted transfers without exactly two matching entries.
+	UnbalancedTransfers []simplebanksql.ListUnbalancedTransfersRow `json:"unbalanced_transfers"`
+}
+
+// Balanced reports whether no inconsistency was found.
+func (d ReconciliationDetails) Balanced() bool {
+	return len(d.DriftedAccounts) == 0 && len(d.OrphanedEntries) == 0 && len(d.UnbalancedTransfers) == 0
+}
+
+// ReconcileTxResult stores the result of a reconciliation.
+type ReconcileTxResult struct {
+	Report  simplebanksql.ReconciliationReport `json:"report"`
+	Details ReconciliationDetails              `json:"details"`
+}
+
+// ReconcileTx verifies every account balance equals the sum of its entries, and every posted transfer has
+// exactly two matching entries. The checks read a single snapshot of the ledger, the report is stored afterwards.
+func (s *SimpleBankDB) ReconcileTx(ctx context.Context) (ReconcileTxResult, error) {
+	var result ReconcileTxResult
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := s.execWithOptions(ctx, opts, func(q *simplebanksql.Queries) error {
+		var err error
+		result.Details.DriftedAccounts, err = q.ListDriftedAccounts(ctx)
+		if err != nil {
+			return err
+		}
+
+		result.Details.OrphanedEntries, err = q.ListOrphanedEntries(ctx)
+		if err != nil {
+			return err
+		}
+
+		result.Details.UnbalancedTransfers, err = q.ListUnbalancedTransfers(ctx)
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+
+	details, err := json.Marshal(result.Details)
+	if err != nil {
+		return result, err
+	}
+
+	result.Report, err = s.CreateReconciliationReport(ctx, simplebanksql.CreateReconciliationReportParams{
+		DriftedAccounts:     int32(len(result.Details.DriftedAccounts)),
+		OrphanedEntries:     int32(len(result.Details.OrphanedEntries)),
+		UnbalancedTransfers: int32(len(result.Details.UnbalancedTransfers)),
+		Details:             details,
+	})
+
+	return result, err
+}
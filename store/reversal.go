@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/money"
+)
+
+var (
+	// ErrTransferNotReversible is returned when reversing a transfer that is itself a reversal,
+	// or a transfer that was not posted.
+	ErrTransferNotReversible = errors.New("transfer cannot be reversed")
+	// ErrRefundExceedsTransfer is returned when the total refunded would exceed the original amount.
+	ErrRefundExceedsTransfer = errors.New("refund exceeds the remaining transfer amount")
+)
+
+// ReverseTransferTxParams stores input params of the reverse transfer transaction.
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount to refund in the currency of the original from account, zero refunds the remaining amount.
+	Amount int64 `json:"amount"`
+}
+
+// ReverseTransferTxResult stores the result of a reverse transfer transaction.
+// The embedded transfer is the compensating transfer from the original to account back to the original from account.
+type ReverseTransferTxResult struct {
+	TransferTxResult
+	OriginalTransfer simplebanksql.Transfer `json:"original_transfer"`
+	// RefundedAmount is the total refunded of the original transfer, this reversal included.
+	RefundedAmount int64 `json:"refunded_amount"`
+}
+
+// ReverseTransferTx creates a compensating transfer linked to the original one with opposite entries
+// within a single db transaction. Partial refunds are allowed until the original amount is fully refunded.
+func (s *SimpleBankDB) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		// Locking the original transfer serializes concurrent refunds of the same transfer.
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if original.ReversedTransferID.Valid {
+			return fmt.Errorf("%w: transfer [%d] is a reversal", ErrTransferNotReversible, original.ID)
+		}
+
+		if original.Status != simplebanksql.TransferStatusPosted {
+			return fmt.Errorf("%w: transfer [%d] is %s", ErrTransferNotReversible, original.ID, original.Status)
+		}
+
+		reversed, err := q.GetReversedAmounts(ctx, original.ID)
+		if err != nil {
+			return err
+		}
+
+		remaining := original.Amount - reversed.RefundedAmount
+		refund := arg.Amount
+		if refund == 0 {
+			refund = remaining
+		}
+
+		if refund <= 0 || refund > remaining {
+			return fmt.Errorf("%w: %d requested, %d remaining", ErrRefundExceedsTransfer, refund, remaining)
+		}
+
+		// The original to account is debited with the rate of the original transfer,
+		// the last refund takes whatever is left so rounding never leaves a residue.
+		debit := original.ToAmount - reversed.ReversedAmount
+		if refund != remaining {
+			debit, err = money.Prorate(original.ToAmount, refund, original.Amount)
+			if err != nil {
+				return err
+			}
+		}
+
+		fromAccount, toAccount, err := lockAccounts(ctx, q, original.ToAccountID, original.FromAccountID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkStatus(fromAccount, toAccount); err != nil {
+			return err
+		}
+
+		if err := checkFunds(fromAccount, debit); err != nil {
+			return err
+		}
+
+		rate, err := money.Reciprocal(original.ExchangeRate, 10)
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = postTransfer(ctx, q, simplebanksql.CreateTransferParams{
+			FromAccountID:      original.ToAccountID,
+			ToAccountID:        original.FromAccountID,
+			Amount:             debit,
+			ToAmount:           refund,
+			ExchangeRate:       rate,
+			ExchangeRateID:     original.ExchangeRateID,
+			ReversedTransferID: sql.NullInt64{Int64: original.ID, Valid: true},
+			Status:             simplebanksql.TransferStatusPosted,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.OriginalTransfer = original
+		result.RefundedAmount = reversed.RefundedAmount + refund
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/access"
+	"github.com/orlandorode97/simple-bank/pkg/schedule"
+)
+
+// ErrScheduledTransferNotDue is returned when the run of a scheduled transfer was already executed,
+// or the scheduled transfer is no longer active.
+var ErrScheduledTransferNotDue = errors.New("scheduled transfer is not due")
+
+// ScheduledTransferTxParams stores input params of a scheduled transfer run.
+type ScheduledTransferTxParams struct {
+	ScheduledTransferID int64 `json:"scheduled_transfer_id"`
+	// RunAt is the run being executed, it must match the next run of the scheduled transfer.
+	RunAt time.Time `json:"run_at"`
+}
+
+// ScheduledTransferTxResult stores the result of a scheduled transfer run.
+type ScheduledTransferTxResult struct {
+	TransferTxResult
+	// ScheduledTransfer is the scheduled transfer already moved to its next run.
+	ScheduledTransfer simplebanksql.ScheduledTransfer `json:"scheduled_transfer"`
+}
+
+// ScheduledTransferTx executes a due run of a scheduled transfer and moves it to its next run within a single db transaction,
+// so a run is never executed twice. The scheduled transfer is completed once its next run falls after its end date.
+func (s *SimpleBankDB) ScheduledTransferTx(ctx context.Context, arg ScheduledTransferTxParams) (ScheduledTransferTxResult, error) {
+	var result ScheduledTransferTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		scheduled, err := lockDueScheduledTransfer(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		// The user who scheduled the transfer may have left the account, or had its role changed since.
+		err = authorizeMember(ctx, q, AuthorizeAccountParams{
+			AccountID: scheduled.FromAccountID,
+			Username:  scheduled.Owner,
+			Action:    access.ActionSpend,
+			Amount:    scheduled.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = s.transfer(ctx, q, TransferTxParams{
+			FromAccountID: scheduled.FromAccountID,
+			ToAccountID:   scheduled.ToAccountID,
+			Amount:        scheduled.Amount,
+			InitiatedBy:   scheduled.Owner,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ScheduledTransfer, err = advanceScheduledTransfer(ctx, q, scheduled, 0)
+		return err
+	})
+
+	return result, err
+}
+
+// SkipScheduledTransferRunTx records a run that failed after all its retries and moves the scheduled transfer to its next run.
+func (s *SimpleBankDB) SkipScheduledTransferRunTx(ctx context.Context, arg ScheduledTransferTxParams) (simplebanksql.ScheduledTransfer, error) {
+	var result simplebanksql.ScheduledTransfer
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		scheduled, err := lockDueScheduledTransfer(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		result, err = advanceScheduledTransfer(ctx, q, scheduled, scheduled.FailedAttempts+1)
+		return err
+	})
+
+	return result, err
+}
+
+// lockDueScheduledTransfer locks the scheduled transfer for update and verifies the run is still pending.
+func lockDueScheduledTransfer(ctx context.Context, q *simplebanksql.Queries, arg ScheduledTransferTxParams) (simplebanksql.ScheduledTransfer, error) {
+	scheduled, err := q.GetScheduledTransferForUpdate(ctx, arg.ScheduledTransferID)
+	if err != nil {
+		return scheduled, err
+	}
+
+	if scheduled.Status != simplebanksql.ScheduledTransferStatusActive || !scheduled.NextRunAt.Equal(arg.RunAt) {
+		return scheduled, ErrScheduledTransferNotDue
+	}
+
+	return scheduled, nil
+}
+
+// advanceScheduledTransfer moves the scheduled transfer to its first run after now.
+// Runs missed while the workers were down are not executed one by one.
+func advanceScheduledTransfer(ctx context.Context, q *simplebanksql.Queries, scheduled simplebanksql.ScheduledTransfer, failedAttempts int32) (simplebanksql.ScheduledTransfer, error) {
+	now := time.Now()
+	next, err := schedule.Next(scheduled.Schedule, now)
+	if err != nil {
+		return scheduled, err
+	}
+
+	status := scheduled.Status
+	if scheduled.EndAt.Valid && next.After(scheduled.EndAt.Time) {
+		status = simplebanksql.ScheduledTransferStatusCompleted
+	}
+
+	return q.UpdateScheduledTransferRun(ctx, simplebanksql.UpdateScheduledTransferRunParams{
+		ID:             scheduled.ID,
+		NextRunAt:      next,
+		LastRunAt:      sql.NullTime{Time: now, Valid: true},
+		Status:         status,
+		FailedAttempts: failedAttempts,
+	})
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+)
+
+var (
+	// ErrRefreshTokenReused is returned when the refresh token of a session already rotated is presented again,
+	// every session of its family is revoked since the token may have been stolen.
+	ErrRefreshTokenReused = errors.New("refresh token already used")
+	// ErrSessionRevoked is returned when the session was revoked before it could be rotated.
+	ErrSessionRevoked = errors.New("session has been revoked")
+)
+
+// RotateSessionTxParams stores input params of the session rotation.
+type RotateSessionTxParams struct {
+	SessionID uuid.UUID
+	// Session is the successor of the session, its family and parent are taken from the rotated session.
+	Session simplebanksql.CreateSessionParams
+}
+
+// RotateSessionTxResult stores the result of the session rotation.
+type RotateSessionTxResult struct {
+	Session simplebanksql.Session
+	// Revoked is the number of sessions of the family revoked when the refresh token was reused.
+	Revoked int64
+}
+
+// RotateSessionTx exchanges the refresh token of a session for the one of its successor, the session can't be
+// rotated again. When it was already rotated the whole family is revoked and ErrRefreshTokenReused is returned.
+func (s *SimpleBankDB) RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error) {
+	var (
+		result RotateSessionTxResult
+		reused bool
+	)
+
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		session, err := q.GetSessionForUpdate(ctx, arg.SessionID)
+		if err != nil {
+			return err
+		}
+
+		if session.RotatedAt.Valid {
+			// The family is revoked within the transaction, the error is returned once it is committed.
+			reused = true
+			result.Revoked, err = q.BlockSessionFamily(ctx, session.FamilyID)
+			return err
+		}
+
+		if session.IsBlocked {
+			return ErrSessionRevoked
+		}
+
+		if _, err := q.RotateSession(ctx, session.ID); err != nil {
+			return err
+		}
+
+		arg.Session.FamilyID = session.FamilyID
+		arg.Session.ParentID = uuid.NullUUID{UUID: session.ID, Valid: true}
+		result.Session, err = q.CreateSession(ctx, arg.Session)
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if reused {
+		return result, ErrRefreshTokenReused
+	}
+
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+)
+
+// ErrDuplicateReference is returned when the external reference of a deposit or withdrawal was already used.
+var ErrDuplicateReference = errors.New("external reference already used")
+
+// SettlementTxParams stores input params of the deposit and withdrawal transactions.
+type SettlementTxParams struct {
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+	// ExternalReference identifies the operation in the external payment system, it can be used only once.
+	ExternalReference string `json:"external_reference"`
+	// InitiatedBy is the user who initiated a withdrawal, withdrawals count towards the spending limits.
+	// It is ignored by deposits.
+	InitiatedBy string `json:"-"`
+}
+
+// DepositTx credits the account with funds coming from outside the bank. The transfer is posted
+// from the cash in system account of the account's currency, so the ledger stays balanced.
+func (s *SimpleBankDB) DepositTx(ctx context.Context, arg SettlementTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		account, systemAccount, err := lockSettlementAccounts(ctx, q, arg, simplebanksql.SystemAccountKindCashIn)
+		if err != nil {
+			return err
+		}
+
+		if err := checkReceiving(account); err != nil {
+			return err
+		}
+
+		// Deposits do not spend, they never count towards the spending limits.
+		arg.InitiatedBy = ""
+
+		result, err = postSettlement(ctx, q, systemAccount, account, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// WithdrawalTx debits the account with funds leaving the bank. The transfer is posted
+// to the cash out system account of the account's currency, so the ledger stays balanced.
+func (s *SimpleBankDB) WithdrawalTx(ctx context.Context, arg SettlementTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
+		account, systemAccount, err := lockSettlementAccounts(ctx, q, arg, simplebanksql.SystemAccountKindCashOut)
+		if err != nil {
+			return err
+		}
+
+		if account.Status != simplebanksql.AccountStatusActive {
+			return fmt.Errorf("%w: account [%d] is %s", ErrAccountNotActive, account.ID, account.Status)
+		}
+
+		if err := checkFunds(account, arg.Amount); err != nil {
+			return err
+		}
+
+		if err := s.checkSpendingLimits(ctx, q, account, arg.InitiatedBy, arg.Amount); err != nil {
+			return err
+		}
+
+		result, err = postSettlement(ctx, q, account, systemAccount, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// lockSettlementAccounts verifies the external reference was not used, and locks the account
+// and the system account of its currency.
+func lockSettlementAccounts(ctx context.Context, q *simplebanksql.Queries, arg SettlementTxParams, kind simplebanksql.SystemAccountKind) (account, systemAccount simplebanksql.Account, err error) {
+	_, err = q.GetTransferByExternalReference(ctx, sql.NullString{String: arg.ExternalReference, Valid: true})
+	if err == nil {
+		err = fmt.Errorf("%w: %s", ErrDuplicateReference, arg.ExternalReference)
+		return
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+
+	if account, err = q.GetAccount(ctx, arg.AccountID); err != nil {
+		return
+	}
+
+	if account.SystemKind.Valid {
+		err = fmt.Errorf("%w: account [%d] is a system account", ErrAccountNotActive, account.ID)
+		return
+	}
+
+	systemAccount, err = q.GetSystemAccount(ctx, simplebanksql.GetSystemAccountParams{
+		SystemKind: simplebanksql.NullSystemAccountKind{SystemAccountKind: kind, Valid: true},
+		CurrencyID: account.CurrencyID,
+	})
+	if err != nil {
+		err = fmt.Errorf("unable to get %s system account of currency [%d]: %w", kind, account.CurrencyID, err)
+		return
+	}
+
+	account, systemAccount, err = lockAccounts(ctx, q, account.ID, systemAccount.ID)
+	return
+}
+
+// postSettlement posts the transfer between a customer account and a system account, both in the same currency.
+func postSettlement(ctx context.Context, q *simplebanksql.Queries, fromAccount, toAccount simplebanksql.Account, arg SettlementTxParams) (TransferTxResult, error) {
+	result, err := postTransfer(ctx, q, simplebanksql.CreateTransferParams{
+		FromAccountID:     fromAccount.ID,
+		ToAccountID:       toAccount.ID,
+		Amount:            arg.Amount,
+		ToAmount:          arg.Amount,
+		ExchangeRate:      sameCurrencyRate,
+		Status:            simplebanksql.TransferStatusPosted,
+		ExternalReference: sql.NullString{String: arg.ExternalReference, Valid: true},
+		InitiatedBy:       sql.NullString{String: arg.InitiatedBy, Valid: arg.InitiatedBy != ""},
+	})
+
+	// A concurrent operation with the same reference is rejected by the unique index.
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+		return result, fmt.Errorf("%w: %s", ErrDuplicateReference, arg.ExternalReference)
+	}
+
+	return result, err
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/limits"
+)
+
+// ErrSpendingLimitExceeded is returned when a transfer initiated by a user would exceed the daily or monthly
+// spending limit of the from account, or of the user in the currency of the from account.
+var ErrSpendingLimitExceeded = errors.New("spending limit exceeded")
+
+// SpendingLimits stores the default spending limits of every currency code, applied to every account
+// and to every user without an override of its own.
+type SpendingLimits struct {
+	Account map[string]limits.Limits
+	User    map[string]limits.Limits
+}
+
+// RemainingLimitsParams stores input params of the remaining limits report.
+type RemainingLimitsParams struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+}
+
+// RemainingLimitsResult reports how much the user can still spend from the account in every window.
+type RemainingLimitsResult struct {
+	Currency string          `json:"currency"`
+	Account  []limits.Status `json:"account"`
+	User     []limits.Status `json:"user"`
+}
+
+// RemainingLimits reports the spending limits of the account and of the user in the currency of the account,
+// along with the volume already spent in every window.
+func (s *SimpleBankDB) RemainingLimits(ctx context.Context, arg RemainingLimitsParams) (RemainingLimitsResult, error) {
+	var result RemainingLimitsResult
+
+	account, err := s.GetAccount(ctx, arg.AccountID)
+	if err != nil {
+		return result, err
+	}
+
+	spending, err := s.spending(ctx, s.Queries, account, arg.Username, time.Now())
+	if err != nil {
+		return result, err
+	}
+
+	result.Currency = spending.currency
+	result.Account = spending.accountLimits.Report(spending.accountUsage)
+	result.User = spending.userLimits.Report(spending.userUsage)
+	return result, nil
+}
+
+// checkSpendingLimits verifies amount fits in the spending limits of the account and of the user who initiated
+// the transfer. The from account must be already locked by the caller, the user is locked here so concurrent
+// transfers of the same user from different accounts are evaluated one at a time.
+func (s *SimpleBankDB) checkSpendingLimits(ctx context.Context, q *simplebanksql.Queries, account simplebanksql.Account, initiatedBy string, amount int64) error {
+	if initiatedBy == "" {
+		return nil
+	}
+
+	if _, err := q.GetUserForUpdate(ctx, initiatedBy); err != nil {
+		return err
+	}
+
+	spending, err := s.spending(ctx, q, account, initiatedBy, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if breach := spending.accountLimits.Check(spending.accountUsage, amount); breach != nil {
+		return fmt.Errorf("%w: %s limit of account [%d] is %d %s, %d remaining", ErrSpendingLimitExceeded,
+			breach.Window, account.ID, breach.Limit, spending.currency, breach.Remaining)
+	}
+
+	if breach := spending.userLimits.Check(spending.userUsage, amount); breach != nil {
+		return fmt.Errorf("%w: %s limit of user %s is %d %s, %d remaining", ErrSpendingLimitExceeded,
+			breach.Window, initiatedBy, breach.Limit, spending.currency, breach.Remaining)
+	}
+
+	return nil
+}
+
+// spending stores the limits of an account and a user in a currency, and what they already spent.
+type spending struct {
+	currency      string
+	accountLimits limits.Limits
+	accountUsage  limits.Usage
+	userLimits    limits.Limits
+	userUsage     limits.Usage
+}
+
+// spending returns the limits and the usage of the account and of the user in the currency of the account.
+func (s *SimpleBankDB) spending(ctx context.Context, q *simplebanksql.Queries, account simplebanksql.Account, username string, now time.Time) (spending, error) {
+	var result spending
+
+	currency, err := q.GetCurrency(ctx, account.CurrencyID)
+	if err != nil {
+		return result, err
+	}
+	result.currency = currency.Code
+	result.accountLimits = s.spendingLimits.Account[result.currency]
+	result.userLimits = s.spendingLimits.User[result.currency]
+
+	override, err := q.GetSpendingLimit(ctx, simplebanksql.GetSpendingLimitParams{
+		Username:   username,
+		CurrencyID: account.CurrencyID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return result, err
+	}
+
+	if override.DailyLimit.Valid {
+		result.userLimits.Daily = override.DailyLimit.Int64
+	}
+
+	if override.MonthlyLimit.Valid {
+		result.userLimits.Monthly = override.MonthlyLimit.Int64
+	}
+
+	accountUsage, err := q.GetAccountSpending(ctx, simplebanksql.GetAccountSpendingParams{
+		AccountID:    account.ID,
+		DailySince:   limits.Daily.Since(now),
+		MonthlySince: limits.Monthly.Since(now),
+	})
+	if err != nil {
+		return result, err
+	}
+	result.accountUsage = limits.Usage{Daily: accountUsage.Daily, Monthly: accountUsage.Monthly}
+
+	userUsage, err := q.GetUserSpending(ctx, simplebanksql.GetUserSpendingParams{
+		Username:     sql.NullString{String: username, Valid: true},
+		CurrencyID:   account.CurrencyID,
+		DailySince:   limits.Daily.Since(now),
+		MonthlySince: limits.Monthly.Since(now),
+	})
+	if err != nil {
+		return result, err
+	}
+	result.userUsage = limits.Usage{Daily: userUsage.Daily, Monthly: userUsage.Monthly}
+
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/statement"
+)
+
+// StatementTxParams stores input params of the statement transaction.
+type StatementTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// From is inclusive and To is exclusive.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// StatementTx loads the entries of the account for the period with the balance after each of them.
+// The balances and the entries are read from a single snapshot of the ledger.
+func (s *SimpleBankDB) StatementTx(ctx context.Context, arg StatementTxParams) (statement.Statement, error) {
+	result := statement.Statement{
+		AccountID:   arg.AccountID,
+		From:        arg.From,
+		To:          arg.To,
+		GeneratedAt: time.Now(),
+	}
+
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := s.execWithOptions(ctx, opts, func(q *simplebanksql.Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		currency, err := q.GetCurrency(ctx, account.CurrencyID)
+		if err != nil {
+			return err
+		}
+
+		result.Owner = account.Owner
+		result.Currency = currency.Code
+		result.Exponent = int(currency.Exponent)
+
+		result.OpeningBalance, err = q.GetAccountBalanceAt(ctx, simplebanksql.GetAccountBalanceAtParams{
+			AccountID: arg.AccountID,
+			At:        arg.From,
+		})
+		if err != nil {
+			return err
+		}
+
+		entries, err := q.ListStatementEntries(ctx, simplebanksql.ListStatementEntriesParams{
+			AccountID: arg.AccountID,
+			FromDate:  arg.From,
+			ToDate:    arg.To,
+		})
+		if err != nil {
+			return err
+		}
+
+		balance := result.OpeningBalance
+		for _, entry := range entries {
+			balance += entry.Amount
+			result.Lines = append(result.Lines, statement.Line{
+				EntryID:               entry.ID,
+				TransferID:            entry.TransferID.Int64,
+				CounterpartyAccountID: entry.CounterpartyAccountID,
+				Amount:                entry.Amount,
+				Balance:               balance,
+				BookedAt:              entry.CreateadAt,
+			})
+		}
+
+		result.ClosingBalance = balance
+		return nil
+	})
+
+	return result, err
+}
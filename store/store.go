@@ -3,15 +3,42 @@ package store
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/pkg/statement"
 )
 
 // Stores provides all needed functional sql database
 type Store interface {
 	Ping() error
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	CaptureTransferTx(ctx context.Context, arg CaptureTransferTxParams) (TransferTxResult, error)
+	VoidTransferTx(ctx context.Context, transferID int64) (ReleaseHoldTxResult, error)
+	ExpireTransferTx(ctx context.Context, transferID int64) (ReleaseHoldTxResult, error)
+	ScheduledTransferTx(ctx context.Context, arg ScheduledTransferTxParams) (ScheduledTransferTxResult, error)
+	SkipScheduledTransferRunTx(ctx context.Context, arg ScheduledTransferTxParams) (simplebanksql.ScheduledTransfer, error)
+	ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParams) (ChangeAccountStatusTxResult, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (simplebanksql.Account, error)
+	AuthorizeAccount(ctx context.Context, arg AuthorizeAccountParams) (simplebanksql.Account, error)
+	InviteAccountMemberTx(ctx context.Context, arg InviteAccountMemberTxParams) (simplebanksql.AccountMember, error)
+	RemoveAccountMemberTx(ctx context.Context, accountID int64, username string) (simplebanksql.AccountMember, error)
+	RemainingLimits(ctx context.Context, arg RemainingLimitsParams) (RemainingLimitsResult, error)
+	CloseAccountTx(ctx context.Context, arg CloseAccountTxParams) (CloseAccountTxResult, error)
+	MovePocketFundsTx(ctx context.Context, arg MovePocketFundsTxParams) (TransferTxResult, error)
+	DepositTx(ctx context.Context, arg SettlementTxParams) (TransferTxResult, error)
+	WithdrawalTx(ctx context.Context, arg SettlementTxParams) (TransferTxResult, error)
+	CreateInterestPlanTx(ctx context.Context, arg CreateInterestPlanTxParams) (CreateInterestPlanTxResult, error)
+	AccrueInterestTx(ctx context.Context, day time.Time) (AccrueInterestTxResult, error)
+	CapitalizeInterestTx(ctx context.Context, arg CapitalizeInterestTxParams) (CapitalizeInterestTxResult, error)
+	StatementTx(ctx context.Context, arg StatementTxParams) (statement.Statement, error)
+	ReconcileTx(ctx context.Context) (ReconcileTxResult, error)
 	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	EnabledCurrency(ctx context.Context, arg EnabledCurrencyParams) (simplebanksql.Currency, error)
+	CreateCurrencyTx(ctx context.Context, arg simplebanksql.CreateCurrencyParams) (CreateCurrencyTxResult, error)
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error)
 	simplebanksql.Querier
 }
 
@@ -19,13 +46,17 @@ type Store interface {
 type SimpleBankDB struct {
 	db *sql.DB
 	*simplebanksql.Queries
+	spendingLimits SpendingLimits
+	currencies     *currencyCatalog
 }
 
-// NewSimpleBankDB returns a *SimpleBankDB.
-func NewSimpleBankDB(db *sql.DB) Store {
+// NewSimpleBankDB returns a *SimpleBankDB, spendingLimits stores the default limits of every currency.
+func NewSimpleBankDB(db *sql.DB, spendingLimits SpendingLimits) Store {
 	return &SimpleBankDB{
-		db:      db,
-		Queries: simplebanksql.New(db),
+		db:             db,
+		Queries:        simplebanksql.New(db),
+		spendingLimits: spendingLimits,
+		currencies:     &currencyCatalog{},
 	}
 }
 
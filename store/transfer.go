@@ -2,16 +2,49 @@ package store
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
 )
 
+var (
+	// ErrIdempotencyKeyConflict is returned when an idempotency key is reused with a different request,
+	// or while another request holding the same key is still in flight.
+	ErrIdempotencyKeyConflict = errors.New("idempotency key already used by a different request")
+	// ErrInsufficientFunds is returned when the balance plus the overdraft limit of the from account,
+	// minus its funds on hold, does not cover the transfer amount.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrSameAccount is returned when a transfer debits and credits the same account.
+	ErrSameAccount = errors.New("from and to accounts must be different")
+)
+
 // TransferTxParams stores input params of the transfer transaction.
+// Amount is debited in the currency of the from account, the to account is credited with
+// the amount converted to its own currency.
 type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// HoldDuration only authorizes the transfer when it is set, the amount is held on the from account
+	// until the transfer is captured, voided, or the hold expires.
+	HoldDuration time.Duration `json:"hold_duration,omitempty"`
+	// Idempotency makes the transfer safe to retry. It is optional.
+	Idempotency *IdempotencyParams `json:"-"`
+	// InitiatedBy is the user who initiated the transfer, the spending limits are only evaluated when it is set.
+	InitiatedBy string `json:"-"`
+}
+
+// IdempotencyParams stores the idempotency key of a request and how long it is kept.
+type IdempotencyParams struct {
+	Key      string
+	Username string
+	Duration time.Duration
 }
 
 // TransferTxResult stores the result of a transfer transaction.
@@ -21,11 +54,22 @@ type TransferTxResult struct {
 	ToAccount   simplebanksql.Account  `json:"to_account"`
 	FromEntry   simplebanksql.Entry    `json:"from_entry"`
 	ToEntry     simplebanksql.Entry    `json:"to_entry"`
+	// ExchangeRate is the rate applied to a transfer between accounts of different currencies.
+	ExchangeRate *simplebanksql.ExchangeRate `json:"exchange_rate,omitempty"`
+	// Hold reserves the amount of an authorized transfer, entries are created once it is captured.
+	Hold *simplebanksql.Hold `json:"hold,omitempty"`
+	// Fee is charged on top of the amount when a fee schedule applies, authorized transfers are charged once captured.
+	Fee *TransferFee `json:"fee,omitempty"`
 }
 
 // execWithContext executes a function within a database transaction.
 func (s *SimpleBankDB) execWithContext(ctx context.Context, fn func(*simplebanksql.Queries) error) error {
-	tx, err := s.db.Begin()
+	return s.execWithOptions(ctx, nil, fn)
+}
+
+// execWithOptions executes a function within a database transaction started with opts.
+func (s *SimpleBankDB) execWithOptions(ctx context.Context, opts *sql.TxOptions, fn func(*simplebanksql.Queries) error) error {
+	tx, err := s.db.BeginTx(ctx, opts)
 	if err != nil {
 		return err
 	}
@@ -35,6 +79,7 @@ func (s *SimpleBankDB) execWithContext(ctx context.Context, fn func(*simplebanks
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
 			return fmt.Errorf("tx error: %v, rollback error: %v", err, rollbackErr)
 		}
+		return err
 	}
 
 	return tx.Commit()
@@ -42,64 +87,187 @@ func (s *SimpleBankDB) execWithContext(ctx context.Context, fn func(*simplebanks
 
 // TransferTx performs a transaction from one account to other account.
 // It creates a transfer record, add record entries, and update account's balance with a single db transaction.
+// When an idempotency key is provided, a replay of the same request returns the original result instead.
 func (s *SimpleBankDB) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 	err := s.execWithContext(ctx, func(q *simplebanksql.Queries) error {
-		var err error
-		// Create transfer.
-		result.Transfer, err = s.CreateTransfer(ctx, simplebanksql.CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
+		if arg.Idempotency != nil {
+			replayed, err := replayTransfer(ctx, q, arg, &result)
+			if err != nil || replayed {
+				return err
+			}
+		}
 
+		var err error
+		result, err = s.transfer(ctx, q, arg)
 		if err != nil {
 			return err
 		}
 
-		// create first from entry.
-		result.FromEntry, err = q.CreateEntry(ctx, simplebanksql.CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
-
-		if err != nil {
-			return err
+		if arg.Idempotency != nil {
+			return saveIdempotencyKey(ctx, q, arg, result)
 		}
 
-		// create second to entry.
-		result.ToEntry, err = q.CreateEntry(ctx, simplebanksql.CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+		return nil
+	})
+
+	return result, err
+}
 
-		if err != nil {
-			return err
-		}
+// transfer locks both accounts, checks the available funds and the spending limits, converts the amount,
+// and posts the transfer, or only holds the amount when the transfer is authorized.
+func (s *SimpleBankDB) transfer(ctx context.Context, q *simplebanksql.Queries, arg TransferTxParams) (TransferTxResult, error) {
+	if arg.FromAccountID == arg.ToAccountID {
+		return TransferTxResult{}, fmt.Errorf("%w: account [%d]", ErrSameAccount, arg.FromAccountID)
+	}
 
-		// TODO: update account's balance
-		// Here would be different scenarios when multiple goroutines will get the account's amount but with different value.
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = s.updateBalance(ctx, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		}
+	fromAccount, toAccount, err := lockAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
 
-		if arg.ToAccountID < arg.FromAccountID {
-			result.ToAccount, result.FromAccount, err = s.updateBalance(ctx, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
-		}
+	if err := checkStatus(fromAccount, toAccount); err != nil {
+		return TransferTxResult{}, err
+	}
+
+	schedule, fee, err := evaluateFee(ctx, q, fromAccount, arg.Amount)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
 
+	if err := checkFunds(fromAccount, arg.Amount+fee); err != nil {
+		return TransferTxResult{}, err
+	}
+
+	if err := s.checkSpendingLimits(ctx, q, fromAccount, arg.InitiatedBy, arg.Amount); err != nil {
+		return TransferTxResult{}, err
+	}
+
+	toAmount, exchangeRate, err := convertAmount(ctx, q, fromAccount, toAccount, arg.Amount)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+
+	transferArg := simplebanksql.CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		ToAmount:      toAmount,
+		ExchangeRate:  sameCurrencyRate,
+		Status:        simplebanksql.TransferStatusPosted,
+		InitiatedBy:   sql.NullString{String: arg.InitiatedBy, Valid: arg.InitiatedBy != ""},
+	}
+
+	if exchangeRate != nil {
+		transferArg.ExchangeRate = exchangeRate.Rate
+		transferArg.ExchangeRateID = sql.NullInt64{Int64: exchangeRate.ID, Valid: true}
+	}
+
+	var result TransferTxResult
+	if arg.HoldDuration > 0 {
+		transferArg.Status = simplebanksql.TransferStatusPending
+		result, err = holdTransfer(ctx, q, transferArg, toAccount, arg.HoldDuration)
+	} else {
+		result, err = postTransfer(ctx, q, transferArg)
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	if schedule != nil && arg.HoldDuration == 0 {
+		result.Fee, result.FromAccount, err = chargeFee(ctx, q, result.Transfer, fromAccount.CurrencyID, schedule, fee)
 		if err != nil {
-			return err
+			return result, err
 		}
+	}
 
-		return nil
+	result.ExchangeRate = exchangeRate
+	return result, nil
+}
+
+// postTransfer creates the transfer record, its entries, and updates the balance of both accounts.
+// Amount is debited from the from account and ToAmount is credited to the to account.
+// Accounts must be already locked by the caller.
+func postTransfer(ctx context.Context, q *simplebanksql.Queries, arg simplebanksql.CreateTransferParams) (TransferTxResult, error) {
+	// Create transfer.
+	transfer, err := q.CreateTransfer(ctx, arg)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+
+	return postEntries(ctx, q, transfer)
+}
+
+// postEntries creates the entries of the transfer and updates the balance of both accounts.
+func postEntries(ctx context.Context, q *simplebanksql.Queries, transfer simplebanksql.Transfer) (TransferTxResult, error) {
+	result := TransferTxResult{Transfer: transfer}
+	var err error
+
+	// create first from entry.
+	result.FromEntry, err = q.CreateEntry(ctx, simplebanksql.CreateEntryParams{
+		AccountID:  transfer.FromAccountID,
+		Amount:     -transfer.Amount,
+		TransferID: sql.NullInt64{Int64: transfer.ID, Valid: true},
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	// create second to entry.
+	result.ToEntry, err = q.CreateEntry(ctx, simplebanksql.CreateEntryParams{
+		AccountID:  transfer.ToAccountID,
+		Amount:     transfer.ToAmount,
+		TransferID: sql.NullInt64{Int64: transfer.ID, Valid: true},
 	})
 
+	if err != nil {
+		return result, err
+	}
+
+	// Accounts are always updated in the same order to avoid deadlocks between concurrent transfers.
+	if transfer.FromAccountID < transfer.ToAccountID {
+		result.FromAccount, result.ToAccount, err = updateBalance(ctx, q, transfer.FromAccountID, -transfer.Amount, transfer.ToAccountID, transfer.ToAmount)
+	}
+
+	if transfer.ToAccountID < transfer.FromAccountID {
+		result.ToAccount, result.FromAccount, err = updateBalance(ctx, q, transfer.ToAccountID, transfer.ToAmount, transfer.FromAccountID, -transfer.Amount)
+	}
+
 	return result, err
 }
 
-func (s SimpleBankDB) updateBalance(ctx context.Context, fromAccountID, fromAmount, toAccountID, toAmount int64) (fromAccount, toAccount simplebanksql.Account, err error) {
+// lockAccounts locks both accounts for update, always in ascending id order to avoid deadlocks between concurrent transfers.
+func lockAccounts(ctx context.Context, q *simplebanksql.Queries, fromAccountID, toAccountID int64) (fromAccount, toAccount simplebanksql.Account, err error) {
+	if fromAccountID < toAccountID {
+		if fromAccount, err = q.GetAccountForUpdate(ctx, fromAccountID); err != nil {
+			return
+		}
+		toAccount, err = q.GetAccountForUpdate(ctx, toAccountID)
+		return
+	}
+
+	if toAccount, err = q.GetAccountForUpdate(ctx, toAccountID); err != nil {
+		return
+	}
+	fromAccount, err = q.GetAccountForUpdate(ctx, fromAccountID)
+	return
+}
+
+// checkFunds verifies the balance plus the overdraft limit of the account, minus the funds on hold, covers amount.
+func checkFunds(account simplebanksql.Account, amount int64) error {
+	available := account.Balance + account.OverdraftLimit - account.HeldBalance
+	if available < amount {
+		return fmt.Errorf("%w: account [%d] has %d available, %d required", ErrInsufficientFunds, account.ID, available, amount)
+	}
+
+	return nil
+}
 
-	fromAccount, err = s.AddAccountBalance(ctx, simplebanksql.AddAccountBalanceParams{
+func updateBalance(ctx context.Context, q *simplebanksql.Queries, fromAccountID, fromAmount, toAccountID, toAmount int64) (fromAccount, toAccount simplebanksql.Account, err error) {
+
+	fromAccount, err = q.AddAccountBalance(ctx, simplebanksql.AddAccountBalanceParams{
 		ID:     fromAccountID,
 		Amount: fromAmount,
 	})
@@ -108,7 +276,7 @@ func (s SimpleBankDB) updateBalance(ctx context.Context, fromAccountID, fromAmou
 		return
 	}
 
-	toAccount, err = s.AddAccountBalance(ctx, simplebanksql.AddAccountBalanceParams{
+	toAccount, err = q.AddAccountBalance(ctx, simplebanksql.AddAccountBalanceParams{
 		ID:     toAccountID,
 		Amount: toAmount,
 	})
@@ -118,3 +286,76 @@ func (s SimpleBankDB) updateBalance(ctx context.Context, fromAccountID, fromAmou
 	}
 	return
 }
+
+// requestHash returns the sha256 of the transfer params, the idempotency key itself is not part of it.
+func (arg TransferTxParams) requestHash() (string, error) {
+	body, err := json.Marshal(arg)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// replayTransfer loads the result stored for the idempotency key of arg.
+// It reports whether the result was replayed.
+func replayTransfer(ctx context.Context, q *simplebanksql.Queries, arg TransferTxParams, result *TransferTxResult) (bool, error) {
+	key, err := q.GetIdempotencyKey(ctx, simplebanksql.GetIdempotencyKeyParams{
+		Username: arg.Idempotency.Username,
+		Key:      arg.Idempotency.Key,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if time.Now().After(key.ExpiresAt) { // expired keys are overwritten by the new request.
+		return false, nil
+	}
+
+	hash, err := arg.requestHash()
+	if err != nil {
+		return false, err
+	}
+
+	if key.RequestHash != hash {
+		return false, ErrIdempotencyKeyConflict
+	}
+
+	if err := json.Unmarshal(key.Response, result); err != nil {
+		return false, fmt.Errorf("unable to unmarshal idempotent response: %w", err)
+	}
+
+	return true, nil
+}
+
+// saveIdempotencyKey stores the result of the transfer under the idempotency key of arg.
+func saveIdempotencyKey(ctx context.Context, q *simplebanksql.Queries, arg TransferTxParams, result TransferTxResult) error {
+	hash, err := arg.requestHash()
+	if err != nil {
+		return err
+	}
+
+	response, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateIdempotencyKey(ctx, simplebanksql.CreateIdempotencyKeyParams{
+		Username:    arg.Idempotency.Username,
+		Key:         arg.Idempotency.Key,
+		RequestHash: hash,
+		TransferID:  result.Transfer.ID,
+		Response:    response,
+		ExpiresAt:   time.Now().Add(arg.Idempotency.Duration),
+	})
+	if errors.Is(err, sql.ErrNoRows) { // a concurrent request stored the key first.
+		return ErrIdempotencyKeyConflict
+	}
+
+	return err
+}
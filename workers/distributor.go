@@ -9,6 +9,9 @@ import (
 
 type TaskDistributor interface {
 	SendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...asynq.Option) error
+	RunScheduledTransfer(ctx context.Context, payload *PayloadRunScheduledTransfer, opts ...asynq.Option) error
+	ExportStatement(ctx context.Context, payload *PayloadExportStatement, opts ...asynq.Option) error
+	NotifyRefreshTokenReused(ctx context.Context, payload *PayloadNotifyRefreshTokenReused, opts ...asynq.Option) error
 }
 
 type RedisTaskDistributor struct {
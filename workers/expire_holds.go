@@ -0,0 +1,53 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/orlandorode97/simple-bank/store"
+	"go.uber.org/zap"
+)
+
+const (
+	taskExpireHolds = "task:expire_holds"
+
+	// expireHoldsSpec is how often expired holds are released.
+	expireHoldsSpec = "@every 1m"
+	// expiredHoldsLimit is the number of expired holds released on each run.
+	expiredHoldsLimit = 100
+)
+
+// ExpireHolds of RedistTaskProcessor releases the holds of pending transfers that were neither captured nor voided in time.
+// It is triggered periodically by the scheduler.
+func (r *RedistTaskProcessor) ExpireHolds(ctx context.Context, task *asynq.Task) error {
+	holds, err := r.store.ListExpiredHolds(ctx, expiredHoldsLimit)
+	if err != nil {
+		return fmt.Errorf("unable to list expired holds: %w", err)
+	}
+
+	var failed int
+	for _, hold := range holds {
+		_, err := r.store.ExpireTransferTx(ctx, hold.TransferID)
+		if errors.Is(err, store.ErrTransferNotPending) { // captured or voided meanwhile.
+			continue
+		}
+
+		if err != nil {
+			failed++
+			r.logger.Errorw("unable to expire hold",
+				zap.Error(err),
+				zap.Int64("transfer_id", hold.TransferID))
+			continue
+		}
+
+		r.logger.Infow("hold expired", zap.Int64("transfer_id", hold.TransferID))
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("unable to expire %d of %d holds", failed, len(holds))
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/orlandorode97/simple-bank/pkg/statement"
+	"github.com/orlandorode97/simple-bank/store"
+	"go.uber.org/zap"
+)
+
+const (
+	taskExportStatement = "task:export_statement"
+
+	statementExportSubject = "Your account statement"
+)
+
+type PayloadExportStatement struct {
+	Username  string           `json:"username"`
+	AccountID int64            `json:"account_id"`
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Format    statement.Format `json:"format"`
+}
+
+// statementExportBody is the data of the statement_export.gohtml template.
+type statementExportBody struct {
+	Username  string
+	AccountID int64
+	From      time.Time
+	To        time.Time
+	Entries   int
+}
+
+// ExportStatement of RedisTaskDistributor creates a task to enqueue.
+func (r *RedisTaskDistributor) ExportStatement(ctx context.Context, payload *PayloadExportStatement, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(taskExportStatement, jsonPayload, opts...)
+	_, err = r.client.EnqueueContext(ctx, task, opts...)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Infow("task enqueued",
+		zap.String("type", task.Type()),
+		zap.ByteString("payload", task.Payload()))
+
+	return nil
+}
+
+// ExportStatement of RedistTaskProcessor renders the statement of a large period and emails it as an attachment.
+func (r *RedistTaskProcessor) ExportStatement(ctx context.Context, task *asynq.Task) error {
+	payload := PayloadExportStatement{}
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("unable to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := r.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return fmt.Errorf("unable to get user: %w", err)
+	}
+
+	stmt, err := r.store.StatementTx(ctx, store.StatementTxParams{
+		AccountID: payload.AccountID,
+		From:      payload.From,
+		To:        payload.To,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to load statement: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "statement")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// The attachment takes the name of the file.
+	path := filepath.Join(dir, payload.Format.Filename(stmt))
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := statement.Render(file, payload.Format, stmt); err != nil {
+		file.Close()
+		return fmt.Errorf("unable to render statement: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	data := &statementExportBody{
+		Username:  user.Username,
+		AccountID: payload.AccountID,
+		From:      payload.From,
+		To:        payload.To,
+		Entries:   len(stmt.Lines),
+	}
+
+	if err := tlp.ExecuteTemplate(&body, "statement_export.gohtml", data); err != nil {
+		return fmt.Errorf("unable to execute statement_export template: %w", err)
+	}
+
+	if err := r.sender.SendEmail(statementExportSubject, body.String(), []string{user.Email}, nil, nil, []string{path}); err != nil {
+		return fmt.Errorf("unable to send statement: %w", err)
+	}
+
+	r.logger.Infow("task processed",
+		zap.String("type", task.Type()),
+		zap.ByteString("payload", task.Payload()))
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/orlandorode97/simple-bank/store"
+	"go.uber.org/zap"
+)
+
+const (
+	taskAccrueInterest     = "task:accrue_interest"
+	taskCapitalizeInterest = "task:capitalize_interest"
+
+	// accrueInterestSpec accrues the interest of the previous day every day at 01:00 UTC.
+	accrueInterestSpec = "0 1 * * *"
+	// capitalizeInterestSpec capitalizes the interest of the previous month on the first day of every month at 02:00 UTC,
+	// once the last day of the month was accrued.
+	capitalizeInterestSpec = "0 2 1 * *"
+)
+
+type PayloadAccrueInterest struct {
+	// Day is the day accrued formatted as 2006-01-02, the previous day is accrued when it is empty.
+	Day string `json:"day"`
+}
+
+// AccrueInterest of RedistTaskProcessor records the interest earned in a day by the accounts attached to an interest plan.
+// It is triggered periodically by the scheduler, a day can be accrued again by enqueueing it with its payload.
+func (r *RedistTaskProcessor) AccrueInterest(ctx context.Context, task *asynq.Task) error {
+	payload := PayloadAccrueInterest{}
+	if len(task.Payload()) != 0 {
+		if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+			return fmt.Errorf("unable to unmarshal payload: %w", asynq.SkipRetry)
+		}
+	}
+
+	day := time.Now().UTC().AddDate(0, 0, -1)
+	if payload.Day != "" {
+		var err error
+		if day, err = time.Parse("2006-01-02", payload.Day); err != nil {
+			return fmt.Errorf("invalid day %q: %w", payload.Day, asynq.SkipRetry)
+		}
+	}
+
+	result, err := r.store.AccrueInterestTx(ctx, day)
+	if err != nil {
+		return fmt.Errorf("unable to accrue interest: %w", err)
+	}
+
+	r.logger.Infow("interest accrued",
+		zap.Time("day", result.Day),
+		zap.Int("accounts", result.Accrued))
+
+	return nil
+}
+
+// CapitalizeInterest of RedistTaskProcessor posts the interest accrued in the previous months to every account.
+// It is triggered periodically by the scheduler.
+func (r *RedistTaskProcessor) CapitalizeInterest(ctx context.Context, task *asynq.Task) error {
+	now := time.Now().UTC()
+	before := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	accountIDs, err := r.store.ListAccountsWithPendingAccruals(ctx, before)
+	if err != nil {
+		return fmt.Errorf("unable to list accounts with pending accruals: %w", err)
+	}
+
+	var failed int
+	for _, accountID := range accountIDs {
+		result, err := r.store.CapitalizeInterestTx(ctx, store.CapitalizeInterestTxParams{
+			AccountID: accountID,
+			Before:    before,
+		})
+		if errors.Is(err, store.ErrAccountNotActive) { // closed before the interest was capitalized.
+			r.logger.Warnw("interest not capitalized", zap.Error(err), zap.Int64("account_id", accountID))
+			continue
+		}
+
+		if err != nil {
+			failed++
+			r.logger.Errorw("unable to capitalize interest",
+				zap.Error(err),
+				zap.Int64("account_id", accountID))
+			continue
+		}
+
+		r.logger.Infow("interest capitalized",
+			zap.Int64("account_id", accountID),
+			zap.Int64("amount", result.Amount),
+			zap.Int64("accruals", result.Accruals))
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("unable to capitalize interest of %d of %d accounts", failed, len(accountIDs))
+	}
+
+	return nil
+}
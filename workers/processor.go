@@ -20,6 +20,14 @@ var tlp *template.Template
 type TaskProcessor interface {
 	Start() error
 	SendVerifyEmail(ctx context.Context, task *asynq.Task) error
+	ProcessScheduledTransfers(ctx context.Context, task *asynq.Task) error
+	RunScheduledTransfer(ctx context.Context, task *asynq.Task) error
+	ExpireHolds(ctx context.Context, task *asynq.Task) error
+	ReconcileLedger(ctx context.Context, task *asynq.Task) error
+	ExportStatement(ctx context.Context, task *asynq.Task) error
+	AccrueInterest(ctx context.Context, task *asynq.Task) error
+	CapitalizeInterest(ctx context.Context, task *asynq.Task) error
+	NotifyRefreshTokenReused(ctx context.Context, task *asynq.Task) error
 }
 
 type RedistTaskProcessor struct {
@@ -27,17 +35,25 @@ type RedistTaskProcessor struct {
 	store  store.Store
 	logger *zap.SugaredLogger
 	sender mail.EmailSender
+	// distributor enqueues the tasks created while processing other tasks.
+	distributor TaskDistributor
 }
 
 // NewRedistTaskProcessor returns a *TaskProcessor
-func NewRedistTaskProcessor(r asynq.RedisConnOpt, store store.Store, logger *zap.SugaredLogger, sender mail.EmailSender) TaskProcessor {
+func NewRedistTaskProcessor(r asynq.RedisConnOpt, store store.Store, logger *zap.SugaredLogger, sender mail.EmailSender, distributor TaskDistributor) TaskProcessor {
 
-	tlp = template.Must(template.ParseFiles("templates/verification_email.gohtml"))
+	tlp = template.Must(template.ParseFiles(
+		"templates/verification_email.gohtml",
+		"templates/scheduled_transfer_failed.gohtml",
+		"templates/statement_export.gohtml",
+		"templates/refresh_token_reused.gohtml",
+	))
 
 	taskProcessor := &RedistTaskProcessor{
-		store:  store,
-		logger: logger,
-		sender: sender,
+		store:       store,
+		logger:      logger,
+		sender:      sender,
+		distributor: distributor,
 	}
 
 	server := asynq.NewServer(r, asynq.Config{
@@ -61,5 +77,13 @@ func NewRedistTaskProcessor(r asynq.RedisConnOpt, store store.Store, logger *zap
 func (r *RedistTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(taskSendVerifyEmail, r.SendVerifyEmail)
+	mux.HandleFunc(taskProcessScheduledTransfers, r.ProcessScheduledTransfers)
+	mux.HandleFunc(taskRunScheduledTransfer, r.RunScheduledTransfer)
+	mux.HandleFunc(taskExpireHolds, r.ExpireHolds)
+	mux.HandleFunc(taskReconcileLedger, r.ReconcileLedger)
+	mux.HandleFunc(taskExportStatement, r.ExportStatement)
+	mux.HandleFunc(taskAccrueInterest, r.AccrueInterest)
+	mux.HandleFunc(taskCapitalizeInterest, r.CapitalizeInterest)
+	mux.HandleFunc(taskNotifyRefreshTokenReused, r.NotifyRefreshTokenReused)
 	return r.server.Start(mux)
 }
@@ -0,0 +1,86 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+
+	"github.com/hibiken/asynq"
+	"github.com/orlandorode97/simple-bank/mail"
+	"github.com/orlandorode97/simple-bank/store"
+	"go.uber.org/zap"
+)
+
+const (
+	taskReconcileLedger = "task:reconcile_ledger"
+
+	// reconcileLedgerSpec runs the reconciliation every day at 03:00 UTC.
+	reconcileLedgerSpec = "0 3 * * *"
+
+	reconciliationReportTemplate = "templates/reconciliation_report.gohtml"
+)
+
+type PayloadReconcileLedger struct {
+	// EmailTo are the operators that receive the report, it is not emailed when empty.
+	EmailTo []string `json:"email_to"`
+}
+
+// ReconcileLedger of RedistTaskProcessor verifies the ledger and stores the report.
+// It is triggered periodically by the scheduler.
+func (r *RedistTaskProcessor) ReconcileLedger(ctx context.Context, task *asynq.Task) error {
+	payload := PayloadReconcileLedger{}
+	if len(task.Payload()) != 0 {
+		if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+			return fmt.Errorf("unable to unmarshal payload: %w", asynq.SkipRetry)
+		}
+	}
+
+	result, err := r.store.ReconcileTx(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to reconcile ledger: %w", err)
+	}
+
+	logFn := r.logger.Infow
+	if !result.Details.Balanced() {
+		logFn = r.logger.Errorw
+	}
+
+	logFn("ledger reconciled",
+		zap.Int64("report_id", result.Report.ID),
+		zap.Int32("drifted_accounts", result.Report.DriftedAccounts),
+		zap.Int32("orphaned_entries", result.Report.OrphanedEntries),
+		zap.Int32("unbalanced_transfers", result.Report.UnbalancedTransfers))
+
+	if len(payload.EmailTo) == 0 {
+		return nil
+	}
+
+	// The report is already stored, retrying would reconcile again.
+	if err := SendReconciliationReport(r.sender, result, payload.EmailTo); err != nil {
+		return fmt.Errorf("unable to send reconciliation report: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return nil
+}
+
+// SendReconciliationReport emails the reconciliation report to the operators.
+func SendReconciliationReport(sender mail.EmailSender, result store.ReconcileTxResult, to []string) error {
+	t, err := template.ParseFiles(reconciliationReportTemplate)
+	if err != nil {
+		return fmt.Errorf("unable to parse reconciliation_report template: %w", err)
+	}
+
+	var body bytes.Buffer
+	if err := t.Execute(&body, result); err != nil {
+		return fmt.Errorf("unable to execute reconciliation_report template: %w", err)
+	}
+
+	subject := fmt.Sprintf("Ledger reconciliation report #%d: balanced", result.Report.ID)
+	if !result.Details.Balanced() {
+		subject = fmt.Sprintf("Ledger reconciliation report #%d: inconsistencies found", result.Report.ID)
+	}
+
+	return sender.SendEmail(subject, body.String(), to, nil, nil, nil)
+}
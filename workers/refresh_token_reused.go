@@ -0,0 +1,76 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+const (
+	taskNotifyRefreshTokenReused = "task:notify_refresh_token_reused"
+
+	refreshTokenReusedSubject = "We signed you out of your sessions"
+)
+
+type PayloadNotifyRefreshTokenReused struct {
+	Username string `json:"username"`
+	// UserAgent and ClientIP are of the request that presented the reused refresh token.
+	UserAgent  string    `json:"user_agent"`
+	ClientIP   string    `json:"client_ip"`
+	Revoked    int64     `json:"revoked"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// NotifyRefreshTokenReused of RedisTaskDistributor creates a task to enqueue.
+func (r *RedisTaskDistributor) NotifyRefreshTokenReused(ctx context.Context, payload *PayloadNotifyRefreshTokenReused, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(taskNotifyRefreshTokenReused, jsonPayload, opts...)
+	_, err = r.client.EnqueueContext(ctx, task, opts...)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Infow("task enqueued",
+		zap.String("type", task.Type()),
+		zap.ByteString("payload", task.Payload()))
+
+	return nil
+}
+
+// NotifyRefreshTokenReused of RedistTaskProcessor emails the user that its sessions were revoked because a
+// refresh token was used twice.
+func (r *RedistTaskProcessor) NotifyRefreshTokenReused(ctx context.Context, task *asynq.Task) error {
+	payload := PayloadNotifyRefreshTokenReused{}
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("unable to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := r.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return fmt.Errorf("unable to get user: %w", err)
+	}
+
+	var body bytes.Buffer
+	if err := tlp.ExecuteTemplate(&body, "refresh_token_reused.gohtml", &payload); err != nil {
+		return fmt.Errorf("unable to execute refresh_token_reused template: %w", err)
+	}
+
+	if err := r.sender.SendEmail(refreshTokenReusedSubject, body.String(), []string{user.Email}, nil, nil, nil); err != nil {
+		return fmt.Errorf("unable to send refresh token reused email: %w", err)
+	}
+
+	r.logger.Infow("task processed",
+		zap.String("type", task.Type()),
+		zap.ByteString("payload", task.Payload()))
+
+	return nil
+}
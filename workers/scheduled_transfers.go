@@ -0,0 +1,161 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/orlandorode97/simple-bank/generated/sql/simplebanksql"
+	"github.com/orlandorode97/simple-bank/store"
+	"go.uber.org/zap"
+)
+
+const (
+	taskProcessScheduledTransfers = "task:process_scheduled_transfers"
+	taskRunScheduledTransfer      = "task:run_scheduled_transfer"
+
+	// processScheduledTransfersSpec is how often due scheduled transfers are picked up.
+	processScheduledTransfersSpec = "@every 1m"
+	// dueScheduledTransfersLimit is the number of due scheduled transfers enqueued on each pick up.
+	dueScheduledTransfersLimit = 100
+	// runScheduledTransferMaxRetry is how many times a failed run is retried before the owner is notified.
+	runScheduledTransferMaxRetry = 5
+
+	scheduledTransferFailedSubject = "Your scheduled transfer could not be completed"
+)
+
+type PayloadRunScheduledTransfer struct {
+	ScheduledTransferID int64     `json:"scheduled_transfer_id"`
+	RunAt               time.Time `json:"run_at"`
+}
+
+// scheduledTransferFailedBody is the data of the scheduled_transfer_failed.gohtml template.
+type scheduledTransferFailedBody struct {
+	Username          string
+	ScheduledTransfer simplebanksql.ScheduledTransfer
+	RunAt             time.Time
+	Reason            string
+}
+
+// RunScheduledTransfer of RedisTaskDistributor enqueues a run of a scheduled transfer.
+// A run is enqueued once, enqueuing it again while it is pending is ignored.
+func (r *RedisTaskDistributor) RunScheduledTransfer(ctx context.Context, payload *PayloadRunScheduledTransfer, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	taskID := fmt.Sprintf("scheduled_transfer:%d:%d", payload.ScheduledTransferID, payload.RunAt.UnixMicro())
+	opts = append([]asynq.Option{asynq.TaskID(taskID), asynq.MaxRetry(runScheduledTransferMaxRetry)}, opts...)
+
+	task := asynq.NewTask(taskRunScheduledTransfer, jsonPayload, opts...)
+	_, err = r.client.EnqueueContext(ctx, task)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	r.logger.Infow("task enqueued",
+		zap.String("type", task.Type()),
+		zap.ByteString("payload", task.Payload()))
+
+	return nil
+}
+
+// ProcessScheduledTransfers of RedistTaskProcessor enqueues a run for every due scheduled transfer.
+// It is triggered periodically by the scheduler.
+func (r *RedistTaskProcessor) ProcessScheduledTransfers(ctx context.Context, task *asynq.Task) error {
+	scheduledTransfers, err := r.store.ListDueScheduledTransfers(ctx, dueScheduledTransfersLimit)
+	if err != nil {
+		return fmt.Errorf("unable to list due scheduled transfers: %w", err)
+	}
+
+	for _, scheduled := range scheduledTransfers {
+		payload := &PayloadRunScheduledTransfer{
+			ScheduledTransferID: scheduled.ID,
+			RunAt:               scheduled.NextRunAt,
+		}
+
+		if err := r.distributor.RunScheduledTransfer(ctx, payload, asynq.Queue(QueueCritial)); err != nil {
+			return fmt.Errorf("unable to enqueue scheduled transfer [%d]: %w", scheduled.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// RunScheduledTransfer of RedistTaskProcessor executes a run of a scheduled transfer.
+// Failed runs are retried, once the retries are exhausted the run is skipped and the owner is notified by email.
+func (r *RedistTaskProcessor) RunScheduledTransfer(ctx context.Context, task *asynq.Task) error {
+	payload := PayloadRunScheduledTransfer{}
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("unable to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	arg := store.ScheduledTransferTxParams{
+		ScheduledTransferID: payload.ScheduledTransferID,
+		RunAt:               payload.RunAt,
+	}
+
+	_, err := r.store.ScheduledTransferTx(ctx, arg)
+	switch {
+	case err == nil:
+		r.logger.Infow("task processed",
+			zap.String("type", task.Type()),
+			zap.ByteString("payload", task.Payload()))
+		return nil
+	case errors.Is(err, store.ErrScheduledTransferNotDue), errors.Is(err, sql.ErrNoRows):
+		// The run was already executed, or the scheduled transfer was paused or deleted meanwhile.
+		r.logger.Infow("scheduled transfer run skipped",
+			zap.Error(err),
+			zap.ByteString("payload", task.Payload()))
+		return nil
+	}
+
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	if retried < maxRetry {
+		return fmt.Errorf("unable to run scheduled transfer: %w", err)
+	}
+
+	scheduled, skipErr := r.store.SkipScheduledTransferRunTx(ctx, arg)
+	if skipErr != nil {
+		return fmt.Errorf("unable to skip scheduled transfer run: %v: %w", err, skipErr)
+	}
+
+	if notifyErr := r.notifyScheduledTransferFailed(ctx, scheduled, payload.RunAt, err); notifyErr != nil {
+		return fmt.Errorf("unable to notify scheduled transfer failure: %w", notifyErr)
+	}
+
+	return fmt.Errorf("scheduled transfer run failed after %d retries: %v: %w", retried, err, asynq.SkipRetry)
+}
+
+// notifyScheduledTransferFailed emails the owner of the scheduled transfer about a failed run.
+func (r *RedistTaskProcessor) notifyScheduledTransferFailed(ctx context.Context, scheduled simplebanksql.ScheduledTransfer, runAt time.Time, reason error) error {
+	user, err := r.store.GetUser(ctx, scheduled.Owner)
+	if err != nil {
+		return fmt.Errorf("unable to get user: %w", err)
+	}
+
+	var body bytes.Buffer
+	data := &scheduledTransferFailedBody{
+		Username:          user.Username,
+		ScheduledTransfer: scheduled,
+		RunAt:             runAt,
+		Reason:            reason.Error(),
+	}
+
+	if err = tlp.ExecuteTemplate(&body, "scheduled_transfer_failed.gohtml", data); err != nil {
+		return fmt.Errorf("unable to execute scheduled_transfer_failed template: %w", err)
+	}
+
+	return r.sender.SendEmail(scheduledTransferFailedSubject, body.String(), []string{user.Email}, nil, nil, nil)
+}
@@ -0,0 +1,56 @@
+package workers
+
+import (
+	"encoding/json"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+// NewScheduler returns an *asynq.Scheduler that enqueues the periodic tasks.
+// The reconciliation report is emailed to operatorEmails when any is provided.
+func NewScheduler(r asynq.RedisConnOpt, logger *zap.SugaredLogger, operatorEmails []string) (*asynq.Scheduler, error) {
+	scheduler := asynq.NewScheduler(r, &asynq.SchedulerOpts{
+		EnqueueErrorHandler: func(task *asynq.Task, opts []asynq.Option, err error) {
+			logger.Errorw("unable to enqueue periodic task",
+				zap.Error(err),
+				zap.String("type", task.Type()))
+		},
+	})
+
+	periodicTasks := map[string]string{
+		taskProcessScheduledTransfers: processScheduledTransfersSpec,
+		taskExpireHolds:               expireHoldsSpec,
+	}
+
+	for taskType, spec := range periodicTasks {
+		if _, err := scheduler.Register(spec, asynq.NewTask(taskType, nil), asynq.Queue(QueueCritial)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Interest is accrued and capitalized once a day or a month, it does not compete with the critical queue.
+	interestTasks := map[string]string{
+		taskAccrueInterest:     accrueInterestSpec,
+		taskCapitalizeInterest: capitalizeInterestSpec,
+	}
+
+	for taskType, spec := range interestTasks {
+		if _, err := scheduler.Register(spec, asynq.NewTask(taskType, nil), asynq.Queue(QueueDefault), asynq.MaxRetry(3)); err != nil {
+			return nil, err
+		}
+	}
+
+	reconcilePayload, err := json.Marshal(&PayloadReconcileLedger{EmailTo: operatorEmails})
+	if err != nil {
+		return nil, err
+	}
+
+	// The reconciliation scans the whole ledger, it does not compete with the critical queue.
+	task := asynq.NewTask(taskReconcileLedger, reconcilePayload)
+	if _, err := scheduler.Register(reconcileLedgerSpec, task, asynq.Queue(QueueDefault), asynq.MaxRetry(3)); err != nil {
+		return nil, err
+	}
+
+	return scheduler, nil
+}